@@ -0,0 +1,144 @@
+package jsongo
+
+import (
+	"errors"
+	"math"
+)
+
+//ErrorAggregateType error if GroupBy is not called on a TypeArray or an aggregation is called on something else than a TypeArray or a TypeMap of TypeArray
+var ErrorAggregateType = errors.New("jsongo: aggregation on a JSONNode which is neither a TypeArray nor a TypeMap of TypeArray")
+
+//GroupBy Return a new TypeMap grouping the elements of that TypeArray by the value at key
+//
+//key is a path relative to each element, like "country" or "address.country".
+//Each group is a TypeArray of deep copies of the elements, the group name is the string value at key or its json text for other values.
+//Elements without a value at key are left out.
+//
+//GroupBy panic with ErrorAggregateType if that JSONNode is not a TypeArray
+func (that *JSONNode) GroupBy(key string) *JSONNode {
+	elems := mustParseKey(key)
+	if that.t != TypeArray {
+		panic(ErrorAggregateType)
+	}
+	groups := (&JSONNode{}).SetType(TypeMap)
+	for i := range that.a {
+		child, ok := that.a[i].find(elems)
+		if !ok || child.t != TypeValue {
+			continue
+		}
+		groups.Map(groupName(child.Get())).At(Append).Copy(that.a[i], true)
+	}
+	return groups
+}
+
+//Count Return a new TypeValue holding the number of elements of that TypeArray
+//
+//Called on a TypeMap of TypeArray (like the result of GroupBy) it returns a TypeMap with the count of each TypeArray.
+//Count and the other aggregations panic with ErrorAggregateType on any other JSONNode
+func (that *JSONNode) Count() *JSONNode {
+	return that.aggregate(func(a []*JSONNode) *JSONNode {
+		ret := &JSONNode{}
+		ret.Val(len(a))
+		return ret
+	})
+}
+
+//SumOf Return a new TypeValue holding the sum of the numbers at key in the elements of that TypeArray, see Count
+func (that *JSONNode) SumOf(key string) *JSONNode {
+	elems := mustParseKey(key)
+	return that.aggregate(func(a []*JSONNode) *JSONNode {
+		sum := 0.
+		eachNumber(a, elems, func(f float64) {
+			sum += f
+		})
+		ret := &JSONNode{}
+		ret.Val(sum)
+		return ret
+	})
+}
+
+//MinOf Return a new TypeValue holding the smallest number at key in the elements of that TypeArray, see Count
+//
+//The result is TypeNull if there is no number
+func (that *JSONNode) MinOf(key string) *JSONNode {
+	return that.extremum(key, math.Min)
+}
+
+//MaxOf Return a new TypeValue holding the biggest number at key in the elements of that TypeArray, see MinOf
+func (that *JSONNode) MaxOf(key string) *JSONNode {
+	return that.extremum(key, math.Max)
+}
+
+func (that *JSONNode) extremum(key string, pick func(x, y float64) float64) *JSONNode {
+	elems := mustParseKey(key)
+	return that.aggregate(func(a []*JSONNode) *JSONNode {
+		ret := &JSONNode{}
+		found := false
+		var best float64
+		eachNumber(a, elems, func(f float64) {
+			if !found {
+				best, found = f, true
+			}
+			best = pick(best, f)
+		})
+		if !found {
+			ret.SetNull()
+			return ret
+		}
+		ret.Val(best)
+		return ret
+	})
+}
+
+//aggregate call f on the elements of that TypeArray, or on each TypeArray of that TypeMap
+func (that *JSONNode) aggregate(f func(a []*JSONNode) *JSONNode) *JSONNode {
+	switch that.t {
+	case TypeArray:
+		return f(that.a)
+	case TypeMap:
+		ret := (&JSONNode{}).SetType(TypeMap)
+		for key, child := range that.m {
+			if child.t != TypeArray {
+				panic(ErrorAggregateType)
+			}
+			ret.m[key] = f(child.a)
+		}
+		return ret
+	}
+	panic(ErrorAggregateType)
+}
+
+//eachNumber call fn with each number found at elems in a, other values are ignored
+func eachNumber(a []*JSONNode, elems []pathElem, fn func(f float64)) {
+	for i := range a {
+		child, ok := a[i].find(elems)
+		if !ok || child.t != TypeValue {
+			continue
+		}
+		if f, ok := toFloat64(indirect(child.Get())); ok {
+			fn(f)
+		}
+	}
+}
+
+//groupName return the name of the group of v
+func groupName(v interface{}) string {
+	v = indirect(v)
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := GetCodec().Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+//mustParseKey parse a key path and panic if it is invalid
+func mustParseKey(key string) []pathElem {
+	elems, err := parsePath(key, false)
+	if err != nil {
+		panic(err)
+	}
+	return elems
+}
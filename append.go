@@ -0,0 +1,134 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+//encodeStatePool hold the encodeStates of AppendJSON to reuse their stacks
+var encodeStatePool = sync.Pool{
+	New: func() interface{} {
+		return &encodeState{}
+	},
+}
+
+//AppendJSON append the json encoding of that JSONNode to dst and return the extended buffer, like the strconv.Append functions
+//
+//Reusing the returned buffer (dst[:0]) across calls avoids allocating for each document,
+//EstimateJSONSize can be used to size it
+func (that *JSONNode) AppendJSON(dst []byte) ([]byte, error) {
+	e := encodeStatePool.Get().(*encodeState)
+	e.buf, e.root = dst, that
+	err := e.encode(that)
+	buf := e.buf
+	*e = encodeState{stack: e.stack[:0], path: e.path[:0], keyBuf: e.keyBuf[:0]}
+	encodeStatePool.Put(e)
+	if err != nil {
+		return dst, err
+	}
+	return buf, nil
+}
+
+var numberType = reflect.TypeOf(json.Number(""))
+
+//stringValue return v if it is a string or a non nil *string, without allocating like indirect would
+func stringValue(v interface{}) (string, bool) {
+	switch vv := v.(type) {
+	case string:
+		return vv, true
+	case *string:
+		if vv != nil {
+			return *vv, true
+		}
+	}
+	return "", false
+}
+
+//appendScalar append v if it is a string, a bool or an integer like encoding/json does, without allocating
+//
+//It returns false if v is something else
+func appendScalar(dst []byte, v interface{}) ([]byte, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return append(dst, "null"...), true
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() || rv.Type().Implements(marshalerType) || rv.Type().Implements(textMarshalerType) {
+			return dst, false
+		}
+		rv = rv.Elem()
+	}
+	rt := rv.Type()
+	if rt == numberType || rt.Implements(marshalerType) || rt.Implements(textMarshalerType) {
+		return dst, false
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return appendString(dst, rv.String()), true
+	case reflect.Bool:
+		return strconv.AppendBool(dst, rv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(dst, rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(dst, rv.Uint(), 10), true
+	}
+	return dst, false
+}
+
+const hexDigits = "0123456789abcdef"
+
+//appendString append s quoted and escaped like encoding/json does
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && n == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += n
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += n
+			start = i
+			continue
+		}
+		i += n
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,37 @@
+package jsongo
+
+import "testing"
+
+func TestAppendJSONAllocs(t *testing.T) {
+	tests := map[string]func(n *JSONNode){
+		"string": func(n *JSONNode) { n.Val("abc") },
+		"int":    func(n *JSONNode) { n.Val(42) },
+		"int64":  func(n *JSONNode) { n.Val(int64(-7)) },
+		"uint8":  func(n *JSONNode) { n.Val(uint8(7)) },
+		"float":  func(n *JSONNode) { n.Val(4.5) },
+		"bool":   func(n *JSONNode) { n.Val(true) },
+		"null":   func(n *JSONNode) { n.SetNull() },
+		"map":    func(n *JSONNode) { n.At("a").Val(1) },
+		"array":  func(n *JSONNode) { n.At(0).Val(1); n.At(1).Val("x") },
+		"mixed": func(n *JSONNode) {
+			if err := n.UnmarshalJSON([]byte(`{"a":[1,"x",true,null,{"b":2.5}],"c":"d"}`)); err != nil {
+				t.Fatal(err)
+			}
+		},
+	}
+	for name, build := range tests {
+		var node JSONNode
+		build(&node)
+		buf, err := node.AppendJSON(make([]byte, 0, 256))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		want, _ := node.MarshalJSON()
+		if string(buf) != string(want) {
+			t.Fatalf("%s: got %s, want %s", name, buf, want)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { buf, _ = node.AppendJSON(buf[:0]) }); allocs != 0 {
+			t.Errorf("%s: AppendJSON allocated %v times per call", name, allocs)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package jsongo
+
+//Resize Turn this JSONNode to a TypeArray and/or set its number of elements to n
+//
+//Existing elements are kept up to n, new elements are TypeUndefined
+func (that *JSONNode) Resize(n int) error {
+	if that.t != TypeUndefined && that.t != TypeArray {
+		return ErrorMultipleType
+	}
+	if n < 0 {
+		return ErrorArrayNegativeValue
+	}
+	if that.frozen {
+		return ErrorFrozen
+	}
+	if n < len(that.a) {
+		that.Truncate(n)
+		return nil
+	}
+	that.mutate()
+	defer that.logChange(that.snapshot())
+	from := len(that.a)
+	that.t = TypeArray
+	that.a = appendElems(that.a, n-len(that.a))
+	that.linkElems(from)
+	return nil
+}
+
+//appendElems append n new TypeUndefined elements to a
+func appendElems(a []*JSONNode, n int) []*JSONNode {
+	elems := make([]JSONNode, n)
+	for i := range elems {
+		a = append(a, &elems[i])
+	}
+	return a
+}
+
+//Truncate remove the elements of that TypeArray from index n, nothing happens if it has n elements or less
+//
+//Truncate panic with ErrorMultipleType if that JSONNode is not a TypeArray or a TypeUndefined
+func (that *JSONNode) Truncate(n int) *JSONNode {
+	if that.t != TypeUndefined && that.t != TypeArray {
+		panic(ErrorMultipleType)
+	}
+	if n < 0 {
+		panic(ErrorArrayNegativeValue)
+	}
+	if n >= len(that.a) {
+		return that
+	}
+	that.mutate()
+	defer that.logChange(that.snapshot())
+	//the removed elements may still be seen by a shallow copy, so they are not reused
+	that.a = that.a[:n:n]
+	return that
+}
+
+//Cap Return the number of elements that TypeArray can hold before its storage is reallocated, 0 for other types
+func (that *JSONNode) Cap() int {
+	if that.t != TypeArray {
+		return 0
+	}
+	return cap(that.a)
+}
+
+//Reserve Turn this JSONNode to a TypeArray and make room for n more elements, so they can be added without reallocating
+//
+//Reserve panic with ErrorMultipleType if that JSONNode is not a TypeArray or a TypeUndefined
+func (that *JSONNode) Reserve(n int) *JSONNode {
+	if that.t != TypeUndefined && that.t != TypeArray {
+		panic(ErrorMultipleType)
+	}
+	if n < 0 {
+		panic(ErrorArrayNegativeValue)
+	}
+	that.mutate()
+	that.t = TypeArray
+	if cap(that.a)-len(that.a) < n {
+		a := make([]*JSONNode, len(that.a), len(that.a)+n)
+		copy(a, that.a)
+		that.a = a
+	}
+	return that
+}
+
+//Page Return a new TypeArray JSONNode with the limit elements of that TypeArray starting at offset, or less if there are not enough
+//
+//if deepCopy is false the elements are shared with that JSONNode, like with Copy, else they are copied recursively.
+//
+//Page panic with ErrorMultipleType if that JSONNode is not a TypeArray or a TypeUndefined and with ErrorArrayNegativeValue if offset or limit is negative
+func (that *JSONNode) Page(offset, limit int, deepCopy bool) *JSONNode {
+	if that.t != TypeUndefined && that.t != TypeArray {
+		panic(ErrorMultipleType)
+	}
+	if offset < 0 || limit < 0 {
+		panic(ErrorArrayNegativeValue)
+	}
+	start := min(offset, len(that.a))
+	end := start + min(limit, len(that.a)-start)
+	return that.slice(start, end, deepCopy)
+}
+
+//Chunk Return that TypeArray split in new TypeArray JSONNode of size elements, the last one having the remaining elements
+//
+//if deepCopy is false the elements are shared with that JSONNode, like with Copy, else they are copied recursively.
+//
+//Chunk panic with ErrorMultipleType if that JSONNode is not a TypeArray or a TypeUndefined and with ErrorArrayNegativeValue if size is not positive
+func (that *JSONNode) Chunk(size int, deepCopy bool) []*JSONNode {
+	if that.t != TypeUndefined && that.t != TypeArray {
+		panic(ErrorMultipleType)
+	}
+	if size <= 0 {
+		panic(ErrorArrayNegativeValue)
+	}
+	chunks := make([]*JSONNode, 0, (len(that.a)+size-1)/size)
+	for start := 0; start < len(that.a); start += size {
+		chunks = append(chunks, that.slice(start, min(start+size, len(that.a)), deepCopy))
+	}
+	return chunks
+}
+
+//slice return a new TypeArray with the elements start to end of that JSONNode
+func (that *JSONNode) slice(start, end int, deepCopy bool) *JSONNode {
+	node := &JSONNode{}
+	if !deepCopy {
+		node.t = TypeArray
+		//growing the new array must not write in the storage of that JSONNode
+		node.a = that.a[start:end:end]
+		return node
+	}
+	node.Resize(end - start)
+	for i := start; i < end; i++ {
+		node.a[i-start].Copy(that.a[i], true)
+	}
+	return node
+}
@@ -0,0 +1,28 @@
+package jsongo
+
+import "testing"
+
+func TestArrayStableElements(t *testing.T) {
+	var root JSONNode
+	first := root.At(0)
+	first.Val("a")
+	for i := 1; i < 100; i++ {
+		root.At(Append).Val(i)
+	}
+	first.Val("b")
+	if got := root.At(0).Get(); got != "b" {
+		t.Fatalf("got %v, the element moved when the array grew", got)
+	}
+	if root.At(0) != first {
+		t.Fatal("At returned another JSONNode for the same element")
+	}
+}
+
+func TestArrayDeprecatedShim(t *testing.T) {
+	var root JSONNode
+	elems := root.Array(2)
+	(*elems)[1].Val(2)
+	if got := root.At(1).Get(); got != 2 {
+		t.Fatalf("got %v, the change made through Array was lost", got)
+	}
+}
@@ -0,0 +1,170 @@
+package jsongo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+//ErrorUnknownCompression error if SaveFileAtomic is given a compression which was not registered
+var ErrorUnknownCompression = errors.New("jsongo: unknown compression")
+
+//Compression is a compression format usable by SaveFileAtomic and LoadFileAuto, see RegisterCompression
+type Compression struct {
+	Name      string                                    //name used in SaveOptions.Compression
+	Magic     []byte                                    //first bytes of a compressed file, used by LoadFileAuto to detect the format
+	NewWriter func(w io.Writer) (io.WriteCloser, error) //return a writer compressing to w
+	NewReader func(r io.Reader) (io.ReadCloser, error)  //return a reader decompressing r
+}
+
+var (
+	compressionsMu sync.RWMutex
+	compressions   = map[string]Compression{
+		"gzip": {
+			Name:  "gzip",
+			Magic: []byte{0x1f, 0x8b},
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriter(w), nil
+			},
+			NewReader: func(r io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(r)
+			},
+		},
+	}
+)
+
+//RegisterCompression add or replace a compression format, gzip is registered by default
+//
+//For zstd with github.com/klauspost/compress/zstd:
+//
+//	jsongo.RegisterCompression(jsongo.Compression{
+//		Name:  "zstd",
+//		Magic: []byte{0x28, 0xb5, 0x2f, 0xfd},
+//		NewWriter: func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+//		NewReader: func(r io.Reader) (io.ReadCloser, error) {
+//			d, err := zstd.NewReader(r)
+//			if err != nil {
+//				return nil, err
+//			}
+//			return d.IOReadCloser(), nil
+//		},
+//	})
+func RegisterCompression(c Compression) {
+	compressionsMu.Lock()
+	defer compressionsMu.Unlock()
+	compressions[c.Name] = c
+}
+
+//SaveOptions are the options of SaveFileAtomic
+type SaveOptions struct {
+	Perm        os.FileMode //permissions of the file, 0644 if 0
+	Compression string      //name of a registered Compression like "gzip", no compression if empty
+}
+
+//SaveFileAtomic write that JSONNode indented in the file at path, so that path holds either the old or the new content even after a crash
+//
+//The json is written and synced in a temporary file of the same directory, which is then renamed to path
+func (that *JSONNode) SaveFileAtomic(path string, opts SaveOptions) (err error) {
+	data, err := json.MarshalIndent(that, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	var compression Compression
+	if opts.Compression != "" {
+		compressionsMu.RLock()
+		c, ok := compressions[opts.Compression]
+		compressionsMu.RUnlock()
+		if !ok {
+			return fmt.Errorf("%w %q", ErrorUnknownCompression, opts.Compression)
+		}
+		compression = c
+	}
+	if opts.Perm == 0 {
+		opts.Perm = 0o644
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	var w io.Writer = tmp
+	var cw io.WriteCloser
+	if compression.NewWriter != nil {
+		if cw, err = compression.NewWriter(tmp); err != nil {
+			return err
+		}
+		w = cw
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if cw != nil {
+		if err = cw.Close(); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Chmod(opts.Perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	//sync the directory so the rename itself survives a crash, not every system allows it
+	if d, errDir := os.Open(dir); errDir == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+//LoadFileAuto Unmarshal the json file at path in a new JSONNode, decompressing it if it starts with the Magic of a registered Compression
+func LoadFileAuto(path string) (*JSONNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	compressionsMu.RLock()
+	var compression *Compression
+	for _, c := range compressions {
+		if len(c.Magic) > 0 && bytes.HasPrefix(data, c.Magic) {
+			compression = &c
+			break
+		}
+	}
+	compressionsMu.RUnlock()
+	if compression != nil {
+		r, err := compression.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		data, err = io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	node := &JSONNode{}
+	if err := json.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
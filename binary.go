@@ -0,0 +1,39 @@
+package jsongo
+
+import (
+	"encoding/base64"
+	"errors"
+)
+
+//ErrorBytesType error if you try to GetBytes on a value that is neither a []byte nor a string
+var ErrorBytesType = errors.New("jsongo: GetBytes: value is neither a []byte nor a string")
+
+//SetBytes Turn this JSONNode to Value type and set it to b
+//
+//b will be marshaled as a standard base64 string like encoding/json does with []byte
+func (that *JSONNode) SetBytes(b []byte) {
+	that.Val(b)
+}
+
+//GetBytes Return the value of a TypeValue as a []byte
+//
+//string values are decoded as standard or URL-safe base64, padded or not
+func (that *JSONNode) GetBytes() ([]byte, error) {
+	if that.t != TypeValue {
+		return nil, ErrorRetrieveUserValue
+	}
+	switch v := that.Get().(type) {
+	case []byte:
+		return v, nil
+	case string:
+		var err error
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			var b []byte
+			if b, err = enc.DecodeString(v); err == nil {
+				return b, nil
+			}
+		}
+		return nil, err
+	}
+	return nil, ErrorBytesType
+}
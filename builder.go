@@ -0,0 +1,90 @@
+package jsongo
+
+import (
+	"runtime"
+)
+
+//Builder wrap a JSONNode so that chained calls never panic
+//
+//The first failure is recorded and every following call on that Builder or any Builder derived from it is a no-op.
+//
+//Use Err to retrieve the failure once you are done building
+type Builder struct {
+	node *JSONNode
+	err  *error //shared by every Builder derived from the same Build call
+}
+
+//Build return a Builder working on that JSONNode
+func (that *JSONNode) Build() *Builder {
+	return &Builder{node: that, err: new(error)}
+}
+
+//do run f on the node unless a failure was already recorded, and record the failure f panicked with
+func (that *Builder) do(f func() *JSONNode) *Builder {
+	if *that.err != nil {
+		return that
+	}
+	next := that.node
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err, ok := r.(error)
+				if _, isRuntime := r.(runtime.Error); !ok || isRuntime {
+					panic(r)
+				}
+				*that.err = err
+			}
+		}()
+		next = f()
+	}()
+	if *that.err != nil || next == that.node {
+		return that
+	}
+	return &Builder{node: next, err: that.err}
+}
+
+//At is the Builder version of JSONNode.At
+func (that *Builder) At(val ...interface{}) *Builder {
+	return that.do(func() *JSONNode { return that.node.At(val...) })
+}
+
+//Map is the Builder version of JSONNode.Map
+func (that *Builder) Map(key string) *Builder {
+	return that.do(func() *JSONNode { return that.node.Map(key) })
+}
+
+//Val is the Builder version of JSONNode.Val
+func (that *Builder) Val(val interface{}) *Builder {
+	return that.do(func() *JSONNode {
+		that.node.Val(val)
+		return that.node
+	})
+}
+
+//SetNull is the Builder version of JSONNode.SetNull
+func (that *Builder) SetNull() *Builder {
+	return that.do(func() *JSONNode {
+		that.node.SetNull()
+		return that.node
+	})
+}
+
+//SetType is the Builder version of JSONNode.SetType
+func (that *Builder) SetType(t JSONNodeType) *Builder {
+	return that.do(func() *JSONNode { return that.node.SetType(t) })
+}
+
+//DelKey is the Builder version of JSONNode.DelKey
+func (that *Builder) DelKey(key string) *Builder {
+	return that.do(func() *JSONNode { return that.node.DelKey(key) })
+}
+
+//Node Return the JSONNode the Builder is working on
+func (that *Builder) Node() *JSONNode {
+	return that.node
+}
+
+//Err Return the first failure recorded, or nil
+func (that *Builder) Err() error {
+	return *that.err
+}
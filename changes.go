@@ -0,0 +1,207 @@
+package jsongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//tracker receive the changes made to a tree, for its Journals and its subscriptions
+type tracker struct {
+	mu       sync.Mutex
+	journals []attachedJournal
+	subs     []*subscription
+	rev      uint64   //revision of the last change, 0 until Revision is called, see stamp
+	merged   *tracker //tracker this one was merged in, see merge
+}
+
+//attachedJournal is a Journal attached to the JSONNode at prefix
+type attachedJournal struct {
+	journal *Journal
+	prefix  []pathElem
+}
+
+//changeLink tie a JSONNode to the tracker of its tree
+type changeLink struct {
+	tracker *tracker
+	path    []pathElem //path from the JSONNode the tracker was attached to
+}
+
+//tracker return the tracker that JSONNode is linked to and its path in it, linking it and its children to a new one if there is none
+//
+//A JSONNode already linked to the tracker of one of its parents reuse it, so the observers of the parents keep receiving its changes
+func (that *JSONNode) tracker() (*tracker, []pathElem) {
+	if that.link != nil {
+		return that.link.tracker, slices.Clone(that.link.path)
+	}
+	t := &tracker{}
+	that.linkTo(t, nil)
+	return t, nil
+}
+
+//untrack unlink that JSONNode and its children from t if t has nothing to send the changes to anymore and no revision to count
+//
+//Only the JSONNode t was created for can unlink it, the others stay linked to it until then
+func (that *JSONNode) untrack(t *tracker) {
+	t.mu.Lock()
+	unused := len(t.journals) == 0 && len(t.subs) == 0 && atomic.LoadUint64(&t.rev) == 0
+	t.mu.Unlock()
+	if unused && that.link != nil && that.link.tracker == t && len(that.link.path) == 0 {
+		that.linkTo(nil, nil)
+	}
+}
+
+//relative return path without prefix, false if path is not under prefix
+func relative(path, prefix []pathElem) ([]pathElem, bool) {
+	if len(path) < len(prefix) || !slices.Equal(path[:len(prefix)], prefix) {
+		return nil, false
+	}
+	return path[len(prefix):], true
+}
+
+//matches return path relative to the JSONNode sub was created on, false if sub does not want the changes at path
+func (sub *subscription) matches(path []pathElem) ([]pathElem, bool) {
+	rel, ok := relative(path, sub.prefix)
+	return rel, ok && matchElems(sub.query.elems, rel) == matchFull
+}
+
+//linkTo link that JSONNode, found at path, and its children to t
+//
+//A tracker created for that JSONNode or one of its children is merged in t, so its observers keep receiving the changes
+func (that *JSONNode) linkTo(t *tracker, path []pathElem) {
+	if t != nil && that.link != nil && that.link.tracker != t && len(that.link.path) == 0 {
+		t.merge(that.link.tracker, path)
+	}
+	if t == nil {
+		that.link = nil
+	} else {
+		that.link = &changeLink{tracker: t, path: path}
+	}
+	switch that.t {
+	case TypeMap:
+		for key, child := range that.m {
+			child.linkTo(t, append(slices.Clip(path), newKeyElem(key)))
+		}
+	case TypeArray:
+		that.linkElems(0)
+	}
+}
+
+//linkKey link the child key of that JSONNode to its tracker
+func (that *JSONNode) linkKey(key string) {
+	if that.link != nil {
+		that.m[key].linkTo(that.link.tracker, append(slices.Clip(that.link.path), newKeyElem(key)))
+	}
+}
+
+//linkElems link the elements of that JSONNode from index from to its tracker
+func (that *JSONNode) linkElems(from int) {
+	if that.link == nil {
+		return
+	}
+	for i := from; i < len(that.a); i++ {
+		that.a[i].linkTo(that.link.tracker, append(slices.Clip(that.link.path), pathElem{index: i, isIndex: true}))
+	}
+}
+
+//merge move the Journals, the subscriptions and the revision of old, created for the JSONNode at path, to t
+func (t *tracker) merge(old *tracker, path []pathElem) {
+	old.mu.Lock()
+	journals, subs, rev := old.journals, old.subs, atomic.LoadUint64(&old.rev)
+	old.journals, old.subs, old.merged = nil, nil, t
+	old.mu.Unlock()
+	for i := range journals {
+		journals[i].prefix = append(slices.Clone(path), journals[i].prefix...)
+	}
+	for _, sub := range subs {
+		sub.prefix = append(slices.Clone(path), sub.prefix...)
+	}
+	t.mu.Lock()
+	t.journals = append(t.journals, journals...)
+	t.subs = append(t.subs, subs...)
+	t.mu.Unlock()
+	if rev > atomic.LoadUint64(&t.rev) {
+		atomic.StoreUint64(&t.rev, rev)
+	}
+}
+
+//current return the tracker t was last merged in, t itself if it was not
+func (t *tracker) current() *tracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.merged == nil {
+		return t
+	}
+	return t.merged.current()
+}
+
+//wants return true if a change at path must be sent somewhere
+func (t *tracker) wants(path []pathElem) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, attached := range t.journals {
+		if _, ok := relative(path, attached.prefix); ok {
+			return true
+		}
+	}
+	for _, sub := range t.subs {
+		if _, ok := sub.matches(path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+//snapshot return the json of that JSONNode if its changes are tracked, to be given to logChange once that JSONNode is modified
+func (that *JSONNode) snapshot() json.RawMessage {
+	if that.link == nil || that.t == TypeUndefined || !that.link.tracker.wants(that.link.path) {
+		return nil
+	}
+	data, err := that.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+//logChange send the change of that JSONNode from old to its tracker, if there is one
+func (that *JSONNode) logChange(old json.RawMessage) {
+	if that.link == nil {
+		return
+	}
+	cur := that.snapshot()
+	if (old == nil) == (cur == nil) && bytes.Equal(old, cur) {
+		return
+	}
+	that.link.tracker.send(that.link.path, old, cur)
+}
+
+//logRemove send the removal of that JSONNode from its parent to its tracker
+func (that *JSONNode) logRemove() {
+	if old := that.snapshot(); old != nil {
+		that.link.tracker.send(that.link.path, old, nil)
+	}
+}
+
+//send record a change in the Journals above path and send it to the matching subscriptions
+func (t *tracker) send(path []pathElem, old, cur json.RawMessage) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, attached := range t.journals {
+		if rel, ok := relative(path, attached.prefix); ok {
+			attached.journal.add(JournalEntry{Path: Path{elems: rel}, Old: old, New: cur, Time: now})
+		}
+	}
+	for _, sub := range t.subs {
+		if rel, ok := sub.matches(path); ok {
+			select {
+			case sub.ch <- Update{Path: Path{elems: rel}, Old: old, New: cur, Time: now}:
+			default:
+			}
+		}
+	}
+}
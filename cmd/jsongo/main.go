@@ -0,0 +1,282 @@
+// Command jsongo expose the jsongo package features from the command line
+//
+// Usage:
+//
+//	jsongo get FILE QUERY        print every node matching QUERY, like '.a.b[0]' or '.items[*].id'
+//	jsongo gjson FILE PATH       print the node at a gjson style PATH
+//	jsongo set FILE PATH VALUE   set VALUE (json, or a plain string) at PATH and print the document
+//	jsongo resolve FILE          resolve the $ref of the document and print it
+//	jsongo tree FILE             print the structure of the document
+//	jsongo hash FILE             print the sha256 of the canonical form of the document
+//	jsongo fmt [-c] FILE         print the document indented, or canonical with -c
+//	jsongo diff [-format unified|side|terse] [-color] FILE1 FILE2
+//	                             print the differences between two documents, exit with 1 if there are some
+//	jsongo merge [-c] BASE PATCH print BASE with PATCH applied as a JSON merge patch (RFC 7386)
+//	jsongo merge3 BASE OURS THEIRS
+//	                             print the three-way merge of the documents, exit with 1 if there are conflicts
+//
+// FILE can be - to read the standard input, for one FILE only.
+// Flags can be given before or after the other arguments, -- ends them
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bennyscetbun/jsongo"
+)
+
+var errUsage = errors.New("usage: jsongo get|gjson|set|resolve|tree|hash|fmt|diff|merge|merge3 FILE [ARGS...]")
+
+//arity is the number of arguments of each command, FILE included
+var arity = map[string]int{
+	"get":     2,
+	"gjson":   2,
+	"set":     3,
+	"resolve": 1,
+	"tree":    1,
+	"hash":    1,
+	"fmt":     1,
+	"diff":    2,
+	"merge":   2,
+	"merge3":  3,
+}
+
+//errDiffer is returned by diff when the documents differ, to exit with 1 without any message
+var errDiffer = errors.New("documents differ")
+
+//errStdinTwice is returned when - is given for more than one FILE
+var errStdinTwice = errors.New("- can be given for one FILE only")
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil && !errors.Is(err, errDiffer) {
+		fmt.Fprintf(os.Stderr, "jsongo: %s\n", err.Error())
+	}
+	os.Exit(exitCode(err))
+}
+
+//exitCode return the exit status for the error returned by run
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	}
+	return 1
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	cmd, args := args[0], args[1:]
+	flags := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	canonical := flags.Bool("c", false, "canonical output")
+	format := flags.String("format", "unified", "diff format: unified, side or terse")
+	color := flags.Bool("color", false, "colorize the diff")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return errUsage
+	}
+	if n, ok := arity[cmd]; !ok || len(args) != n {
+		return errUsage
+	}
+	root, err := load(args[0], &stdin)
+	if err != nil {
+		return err
+	}
+	switch cmd {
+	case "get":
+		q, err := jsongo.CompileQuery(strings.TrimPrefix(args[1], "."))
+		if err != nil {
+			return err
+		}
+		found := q.Find(root)
+		if len(found) == 0 {
+			return fmt.Errorf("%w: %q", jsongo.ErrorPathNotFound, args[1])
+		}
+		for _, node := range found {
+			if err := output(stdout, node, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "gjson":
+		node, err := root.GetGJSON(args[1])
+		if err != nil {
+			return err
+		}
+		return output(stdout, node, false)
+	case "set":
+		p, err := jsongo.ParsePath(strings.TrimPrefix(args[1], "."))
+		if err != nil {
+			return err
+		}
+		var val interface{}
+		if json.Unmarshal([]byte(args[2]), &val) != nil {
+			val = args[2]
+		}
+		if err := p.Set(root, val); err != nil {
+			return err
+		}
+		return output(stdout, root, *canonical)
+	case "resolve":
+		dir := filepath.Dir(args[0])
+		err := root.ResolveRefs(jsongo.RefOptions{Loader: func(uri string) (*jsongo.JSONNode, error) {
+			return load(filepath.Join(dir, uri), nil)
+		}})
+		if err != nil {
+			return err
+		}
+		return output(stdout, root, *canonical)
+	case "tree":
+		root.Tree(stdout)
+		return nil
+	case "hash":
+		_, err := fmt.Fprintf(stdout, "%x\n", root.Hash())
+		return err
+	case "fmt":
+		return output(stdout, root, *canonical)
+	case "diff":
+		other, err := load(args[1], &stdin)
+		if err != nil {
+			return err
+		}
+		return diff(stdout, root, other, *format, *color)
+	case "merge":
+		patch, err := load(args[1], &stdin)
+		if err != nil {
+			return err
+		}
+		return output(stdout, jsongo.MergePatch(root, patch), *canonical)
+	case "merge3":
+		ours, err := load(args[1], &stdin)
+		if err != nil {
+			return err
+		}
+		theirs, err := load(args[2], &stdin)
+		if err != nil {
+			return err
+		}
+		merged, conflicts, err := jsongo.Merge3(root, ours, theirs)
+		if err != nil {
+			return err
+		}
+		if err := output(stdout, merged, *canonical); err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			paths := make([]string, len(conflicts))
+			for i := range conflicts {
+				paths[i] = conflicts[i].Path.String()
+			}
+			return fmt.Errorf("%d conflicts: %s", len(conflicts), strings.Join(paths, ", "))
+		}
+		return nil
+	}
+	return errUsage
+}
+
+//parseArgs parse the flags found anywhere in args and return the other arguments
+//
+//Negative numbers are arguments, everything after -- too
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var flagArgs, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(positional, args[i+1:]...), flags.Parse(flagArgs)
+		case len(arg) < 2 || arg[0] != '-' || json.Valid([]byte(arg)):
+			positional = append(positional, arg)
+		default:
+			flagArgs = append(flagArgs, arg)
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			f := flags.Lookup(name)
+			if f != nil && !hasValue && !isBoolFlag(f) && i+1 < len(args) {
+				i++
+				flagArgs = append(flagArgs, args[i])
+			}
+		}
+	}
+	return positional, flags.Parse(flagArgs)
+}
+
+//isBoolFlag return true if f does not take a value, like -c
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+//load read a json document from a file, or from *stdin if name is -
+//
+//*stdin is set to nil once read, stdin is nil when - is a file name
+func load(name string, stdin *io.Reader) (*jsongo.JSONNode, error) {
+	var data []byte
+	var err error
+	if name == "-" && stdin != nil {
+		if *stdin == nil {
+			return nil, errStdinTwice
+		}
+		data, err = io.ReadAll(*stdin)
+		*stdin = nil
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	root := &jsongo.JSONNode{}
+	if err := json.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return root, nil
+}
+
+//diff write the differences between from and to
+func diff(w io.Writer, from, to *jsongo.JSONNode, format string, color bool) error {
+	formats := map[string]jsongo.DiffFormat{
+		"unified": jsongo.FormatUnified,
+		"side":    jsongo.FormatSideBySide,
+		"terse":   jsongo.FormatTerse,
+	}
+	f, ok := formats[format]
+	if !ok {
+		return errUsage
+	}
+	if color {
+		f |= jsongo.FormatColor
+	}
+	result := jsongo.Diff(from, to)
+	if result.Equal() {
+		return nil
+	}
+	if err := result.Format(w, f); err != nil {
+		return err
+	}
+	return errDiffer
+}
+
+//output write a node indented, or in canonical form
+func output(w io.Writer, node *jsongo.JSONNode, canonical bool) error {
+	var data []byte
+	var err error
+	if canonical {
+		data, err = node.MarshalCanonical()
+	} else {
+		data, err = json.MarshalIndent(node, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//write create the file name in dir with content and return its path
+func write(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	doc := write(t, dir, "doc.json", `{"a":{"b":[1,2]},"s":"x"}`)
+	other := write(t, dir, "other.json", `{"a":{"b":[1,3]},"s":"x"}`)
+	patch := write(t, dir, "patch.json", `{"a":{"c":true},"s":null}`)
+	ours := write(t, dir, "ours.json", `{"a":{"b":[1,2]},"s":"ours"}`)
+	theirs := write(t, dir, "theirs.json", `{"a":{"b":[1,2]},"s":"theirs"}`)
+	ref := write(t, dir, "ref.json", `{"x":{"$ref":"doc.json#/s"}}`)
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string //substring of the output
+		code  int
+	}{
+		{"get", []string{"get", doc, ".a.b[1]"}, "", "2", 0},
+		{"get missing", []string{"get", doc, ".z"}, "", "", 1},
+		{"gjson", []string{"gjson", doc, "a.b.0"}, "", "1", 0},
+		{"set", []string{"set", doc, ".a.b[0]", "-5", "-c"}, "", `{"a":{"b":[-5,2]},"s":"x"}`, 0},
+		{"resolve", []string{"resolve", ref, "-c"}, "", `{"x":"x"}`, 0},
+		{"tree", []string{"tree", doc}, "", "b", 0},
+		{"hash", []string{"hash", doc}, "", "", 0},
+		{"fmt", []string{"fmt", doc, "-c"}, "", `{"a":{"b":[1,2]},"s":"x"}`, 0},
+		{"fmt stdin", []string{"fmt", "-c", "-"}, `[true]`, `[true]`, 0},
+		{"diff equal", []string{"diff", doc, doc}, "", "", 0},
+		{"diff", []string{"diff", "-format", "terse", doc, other}, "", "a.b[1]", 1},
+		{"merge", []string{"merge", "-c", doc, patch}, "", `{"a":{"b":[1,2],"c":true}}`, 0},
+		{"merge stdin", []string{"merge", "-c", "-", patch}, `{"s":1}`, `{"a":{"c":true}}`, 0},
+		{"merge3", []string{"merge3", "-c", doc, doc, theirs}, "", `{"a":{"b":[1,2]},"s":"theirs"}`, 0},
+		{"merge3 conflict", []string{"merge3", doc, ours, theirs}, "", "ours", 1},
+		{"stdin twice", []string{"diff", "-", "-"}, `{}`, "", 1},
+		{"no command", []string{}, "", "", 2},
+		{"unknown command", []string{"bogus", filepath.Join(dir, "missing.json")}, "", "", 2},
+		{"unknown flag", []string{"fmt", "-x", doc}, "", "", 2},
+		{"missing argument", []string{"get", doc}, "", "", 2},
+		{"extra argument", []string{"hash", doc, doc}, "", "", 2},
+		{"unknown format", []string{"diff", "-format", "bogus", doc, other}, "", "", 2},
+		{"missing file", []string{"fmt", filepath.Join(dir, "missing.json")}, "", "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := run(tt.args, strings.NewReader(tt.stdin), &stdout)
+			if code := exitCode(err); code != tt.code {
+				t.Fatalf("exit code %d, want %d (error %v)", code, tt.code, err)
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Fatalf("output %q does not contain %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStdinOnce(t *testing.T) {
+	err := run([]string{"merge3", "-", "-", "-"}, strings.NewReader(`{}`), &bytes.Buffer{})
+	if !errors.Is(err, errStdinTwice) {
+		t.Fatalf("got error %v, want errStdinTwice", err)
+	}
+}
+
+func TestRunValidatesBeforeLoading(t *testing.T) {
+	stdin := strings.NewReader(`{}`)
+	if err := run([]string{"bogus", "-"}, stdin, &bytes.Buffer{}); !errors.Is(err, errUsage) {
+		t.Fatalf("got error %v, want errUsage", err)
+	}
+	if err := run([]string{"get", "-"}, stdin, &bytes.Buffer{}); !errors.Is(err, errUsage) {
+		t.Fatalf("got error %v, want errUsage", err)
+	}
+	if stdin.Len() != 2 {
+		t.Fatal("the standard input was read")
+	}
+}
@@ -0,0 +1,58 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+//Codec encode and decode the values set with Val
+//
+//Marshal write the structure of the tree (maps, arrays, null) itself, only the user values go through the Codec.
+//Unmarshal use the Codec for the values and to split maps and arrays, given as map[string]json.RawMessage and []json.RawMessage.
+//A Codec must behave like encoding/json for the types it is given, see SetCodec
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+//StdCodec is the default Codec, it uses encoding/json
+type StdCodec struct{}
+
+//Marshal call json.Marshal
+func (StdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+//Unmarshal call json.Unmarshal
+func (StdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+//codecHolder let atomic.Value store Codecs of different types
+type codecHolder struct {
+	Codec
+}
+
+var currentCodec atomic.Value
+
+func init() {
+	if currentCodec.Load() == nil {
+		currentCodec.Store(codecHolder{StdCodec{}})
+	}
+}
+
+//SetCodec replace the Codec used by every JSONNode, nil restores StdCodec
+//
+//Building with the jsoniter or sonic tag makes JSONIterCodec or SonicCodec the default Codec.
+//JSONv2Codec is available when building with GOEXPERIMENT=jsonv2
+func SetCodec(c Codec) {
+	if c == nil {
+		c = StdCodec{}
+	}
+	currentCodec.Store(codecHolder{c})
+}
+
+//GetCodec Return the Codec in use
+func GetCodec() Codec {
+	return currentCodec.Load().(codecHolder).Codec
+}
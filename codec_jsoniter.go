@@ -0,0 +1,24 @@
+//go:build jsoniter
+
+package jsongo
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+//JSONIterCodec is a Codec using jsoniter configured to be compatible with encoding/json
+type JSONIterCodec struct{}
+
+func init() {
+	currentCodec.Store(codecHolder{JSONIterCodec{}})
+}
+
+//Marshal call jsoniter.Marshal
+func (JSONIterCodec) Marshal(v interface{}) ([]byte, error) {
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(v)
+}
+
+//Unmarshal call jsoniter.Unmarshal
+func (JSONIterCodec) Unmarshal(data []byte, v interface{}) error {
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(data, v)
+}
@@ -0,0 +1,22 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package jsongo
+
+import (
+	jsonv2 "encoding/json/v2"
+)
+
+//JSONv2Codec is a Codec using encoding/json/v2 and its default options
+//
+//Beware that v2 does not behave exactly like encoding/json, nil slices are written [] for example
+type JSONv2Codec struct{}
+
+//Marshal call json/v2.Marshal
+func (JSONv2Codec) Marshal(v interface{}) ([]byte, error) {
+	return jsonv2.Marshal(v)
+}
+
+//Unmarshal call json/v2.Unmarshal
+func (JSONv2Codec) Unmarshal(data []byte, v interface{}) error {
+	return jsonv2.Unmarshal(data, v)
+}
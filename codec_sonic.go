@@ -0,0 +1,24 @@
+//go:build sonic
+
+package jsongo
+
+import (
+	"github.com/bytedance/sonic"
+)
+
+//SonicCodec is a Codec using sonic configured to be compatible with encoding/json
+type SonicCodec struct{}
+
+func init() {
+	currentCodec.Store(codecHolder{SonicCodec{}})
+}
+
+//Marshal call sonic.Marshal
+func (SonicCodec) Marshal(v interface{}) ([]byte, error) {
+	return sonic.ConfigStd.Marshal(v)
+}
+
+//Unmarshal call sonic.Unmarshal
+func (SonicCodec) Unmarshal(data []byte, v interface{}) error {
+	return sonic.ConfigStd.Unmarshal(data, v)
+}
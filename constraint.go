@@ -0,0 +1,155 @@
+package jsongo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+//ErrorConstraint error if a value is rejected by a Constraint, the errors returned by Val and Unmarshal wrap it
+var ErrorConstraint = errors.New("jsongo: value rejected by a constraint")
+
+//Constraint check a value before it is set in a JSONNode, see Constrain
+//
+//v is what Get will return once the value is set. A non nil error rejects the value
+type Constraint func(v interface{}) error
+
+//Constrain add constraints that every value set in that JSONNode must pass
+//
+//Val panic and Unmarshal return an error wrapping ErrorConstraint when a value is rejected,
+//the current value of that JSONNode is then left unchanged.
+//Computed values (see Compute) are not checked
+func (that *JSONNode) Constrain(constraints ...Constraint) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.constrain = append(slices.Clip(o.constrain), constraints...) })
+	return that
+}
+
+//Unconstrain remove every constraint of that JSONNode
+func (that *JSONNode) Unconstrain() *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.constrain = nil })
+	return that
+}
+
+//Enum set the only values that can be set in that JSONNode, checked like a Constraint
+//
+//Numbers are compared by value so Enum(1, 2) accepts the float64 2 produced by Unmarshal.
+//Enum without values remove the restriction
+func (that *JSONNode) Enum(values ...interface{}) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.enum = values })
+	return that
+}
+
+//check run the enum and the constraints of that JSONNode on val
+func (that *JSONNode) check(val interface{}) error {
+	o := that.opts()
+	if len(o.enum) > 0 && !enumContains(o.enum, val) {
+		return fmt.Errorf("%w: %s is not one of %s", ErrorConstraint, valuePreview(indirect(val)), valuePreview(o.enum))
+	}
+	for _, constraint := range o.constrain {
+		if err := constraint(val); err != nil {
+			if errors.Is(err, ErrorConstraint) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ErrorConstraint, err)
+		}
+	}
+	return nil
+}
+
+//MinLen Return a Constraint rejecting strings (counted in runes), slices and maps shorter than n
+func MinLen(n int) Constraint {
+	return func(v interface{}) error {
+		l, ok := length(v)
+		if !ok {
+			return fmt.Errorf("%w: %T has no length", ErrorConstraint, v)
+		}
+		if l < n {
+			return fmt.Errorf("%w: length %d is less than %d", ErrorConstraint, l, n)
+		}
+		return nil
+	}
+}
+
+//MaxLen Return a Constraint rejecting strings (counted in runes), slices and maps longer than n
+func MaxLen(n int) Constraint {
+	return func(v interface{}) error {
+		l, ok := length(v)
+		if !ok {
+			return fmt.Errorf("%w: %T has no length", ErrorConstraint, v)
+		}
+		if l > n {
+			return fmt.Errorf("%w: length %d is more than %d", ErrorConstraint, l, n)
+		}
+		return nil
+	}
+}
+
+//Range Return a Constraint rejecting non numbers and numbers outside [min, max]
+func Range(min, max float64) Constraint {
+	return func(v interface{}) error {
+		f, ok := toFloat64(indirect(v))
+		if !ok {
+			return fmt.Errorf("%w: %T is not a number", ErrorConstraint, v)
+		}
+		if f < min || f > max {
+			return fmt.Errorf("%w: %v is not in [%v, %v]", ErrorConstraint, f, min, max)
+		}
+		return nil
+	}
+}
+
+//Matches Return a Constraint rejecting non strings and strings not matching re
+func Matches(re *regexp.Regexp) Constraint {
+	return func(v interface{}) error {
+		s, ok := indirect(v).(string)
+		if !ok {
+			return fmt.Errorf("%w: %T is not a string", ErrorConstraint, v)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%w: %q does not match %q", ErrorConstraint, s, re.String())
+		}
+		return nil
+	}
+}
+
+//enumContains return true if val is one of values
+func enumContains(values []interface{}, val interface{}) bool {
+	val = indirect(val)
+	f, isNumber := toFloat64(val)
+	for _, allowed := range values {
+		if isNumber {
+			if g, ok := toFloat64(allowed); ok && f == g {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(allowed, val) {
+			return true
+		}
+	}
+	return false
+}
+
+//indirect return what v points to if v is a non nil pointer
+func indirect(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		return rv.Elem().Interface()
+	}
+	return v
+}
+
+//length return the length of a string, slice, array or map
+func length(v interface{}) (int, bool) {
+	rv := reflect.ValueOf(indirect(v))
+	switch rv.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,52 @@
+package jsongo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestComputeConstrained(t *testing.T) {
+	var node JSONNode
+	node.Constrain(MinLen(3))
+	node.Compute(func(*JSONNode) interface{} { return "ab" })
+	data, err := node.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"ab"` {
+		t.Fatalf("got %s", data)
+	}
+}
+
+func TestSetTypeValueConstrained(t *testing.T) {
+	var node JSONNode
+	node.Constrain(MinLen(3)).SetType(TypeValue)
+	if node.GetType() != TypeValue {
+		t.Fatalf("got type %v", node.GetType())
+	}
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrorConstraint) {
+			t.Fatalf("Val did not panic with ErrorConstraint: %v", err)
+		}
+	}()
+	node.Val("ab")
+}
+
+func TestEnumInternalNil(t *testing.T) {
+	var computed JSONNode
+	computed.Enum("a", "b")
+	computed.Compute(func(*JSONNode) interface{} { return "a" })
+	if data, err := computed.MarshalJSON(); err != nil || string(data) != `"a"` {
+		t.Fatalf("got %s, %v", data, err)
+	}
+
+	var typed JSONNode
+	typed.Enum("a", "b").SetType(TypeValue)
+	typed.Val("b")
+	if typed.Get() != "b" {
+		t.Fatalf("got %v", typed.Get())
+	}
+	if err := typed.UnmarshalJSON([]byte(`"c"`)); !errors.Is(err, ErrorConstraint) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package jsongo
+
+import (
+	"context"
+	"io"
+)
+
+//contextCheckInterval is the number of JSONNode decoded between two checks of the context of UnmarshalContext
+const contextCheckInterval = 256
+
+//UnmarshalContext is UnmarshalJSON stopping with the error of ctx once it is cancelled or past its deadline
+//
+//ctx is checked every few values, the part of data decoded before is kept in that JSONNode
+func (that *JSONNode) UnmarshalContext(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return that.unmarshal(data, &decodeState{ctx: ctx})
+}
+
+//DecodeContext read r until EOF and unmarshal it in that JSONNode, stopping with the error of ctx once it is cancelled or past its deadline
+//
+//ctx is checked between each read of r and during the decoding like UnmarshalContext.
+//A read blocked in r is not interrupted, close r when ctx is done for that
+func (that *JSONNode) DecodeContext(ctx context.Context, r io.Reader) error {
+	data, err := io.ReadAll(&contextReader{ctx: ctx, r: r})
+	if err != nil {
+		return err
+	}
+	return that.UnmarshalContext(ctx, data)
+}
+
+//contextReader is a reader failing with the error of ctx once it is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+//checkContext return the error of the context of UnmarshalContext every contextCheckInterval calls
+func (d *decodeState) checkContext() error {
+	if d.ctx == nil {
+		return nil
+	}
+	d.steps++
+	if d.steps%contextCheckInterval != 0 {
+		return nil
+	}
+	if err := d.ctx.Err(); err != nil {
+		return d.pathError(err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package jsongo
+
+import (
+	"errors"
+)
+
+//ErrorNotCollapsible error if ToValue is called on a TypeArray with more than one element
+var ErrorNotCollapsible = errors.New("jsongo: ToValue: array has more than one element")
+
+//ToArrayNode turn that JSONNode into a TypeArray whose only element is what that JSONNode was
+//
+//A TypeArray is left as is and a TypeUndefined become an empty TypeArray, so calling ToArrayNode on "x": {...}
+//and on "x": [{...}] give the same shape. The options of that JSONNode (constraints, tags...) move to the element
+//
+//ToArrayNode panic with ErrorFrozen if that JSONNode is frozen
+//
+//return the current JSONNode
+func (that *JSONNode) ToArrayNode() *JSONNode {
+	if that.frozen {
+		panic(ErrorFrozen)
+	}
+	switch that.t {
+	case TypeArray:
+	case TypeUndefined:
+		that.SetType(TypeArray)
+	default:
+		that.mutate()
+		inner := *that
+		that.replace(JSONNode{t: TypeArray, a: []*JSONNode{&inner}})
+	}
+	return that
+}
+
+//ToValue turn that TypeArray with a single element into this element, the opposite of ToArrayNode
+//
+//An empty TypeArray become a TypeUndefined and other types are left as is.
+//ToValue return ErrorNotCollapsible and does nothing if that TypeArray has more than one element, ErrorFrozen if it is frozen
+func (that *JSONNode) ToValue() error {
+	if that.t != TypeArray {
+		return nil
+	}
+	if that.frozen {
+		return ErrorFrozen
+	}
+	if len(that.a) > 1 {
+		return ErrorNotCollapsible
+	}
+	that.mutate()
+	if len(that.a) == 0 {
+		that.replace(JSONNode{})
+	} else {
+		that.replace(*that.a[0])
+	}
+	return nil
+}
+
+//WrapIn turn that JSONNode into a TypeMap whose only child, at key, is what that JSONNode was
+//
+//The options of that JSONNode (constraints, tags...) move to the child.
+//
+//WrapIn panic with ErrorFrozen if that JSONNode is frozen
+//
+//return the current JSONNode
+func (that *JSONNode) WrapIn(key string) *JSONNode {
+	if that.frozen {
+		panic(ErrorFrozen)
+	}
+	that.mutate()
+	inner := *that
+	that.replace(JSONNode{t: TypeMap, m: map[string]*JSONNode{key: &inner}})
+	return that
+}
+
+//replace set that JSONNode to node, keeping its place in the tree it is tracked in
+func (that *JSONNode) replace(node JSONNode) {
+	link := that.link
+	defer that.logChange(that.snapshot())
+	*that = node
+	that.link = nil
+	if link != nil {
+		that.linkTo(link.tracker, link.path)
+	}
+	that.stamp()
+}
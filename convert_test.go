@@ -0,0 +1,47 @@
+package jsongo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToValueFrozen(t *testing.T) {
+	var node JSONNode
+	node.At(0).Val(1)
+	node.Freeze()
+	if err := node.ToValue(); !errors.Is(err, ErrorFrozen) {
+		t.Fatalf("got %v, want ErrorFrozen", err)
+	}
+	if node.GetType() != TypeArray {
+		t.Fatalf("ToValue changed a frozen node into %v", node.GetType())
+	}
+}
+
+func TestToValue(t *testing.T) {
+	var node JSONNode
+	node.At(0).Val(1)
+	if err := node.ToValue(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := node.MarshalJSON(); string(data) != "1" {
+		t.Fatalf("got %s", data)
+	}
+	node.Unset()
+	node.At(0).Val(1)
+	node.At(1).Val(2)
+	if err := node.ToValue(); !errors.Is(err, ErrorNotCollapsible) {
+		t.Fatalf("got %v, want ErrorNotCollapsible", err)
+	}
+}
+
+func TestWrapInFrozen(t *testing.T) {
+	var node JSONNode
+	node.Val(1)
+	node.Freeze()
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrorFrozen) {
+			t.Fatalf("WrapIn did not panic with ErrorFrozen: %v", err)
+		}
+	}()
+	node.WrapIn("a")
+}
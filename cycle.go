@@ -0,0 +1,205 @@
+package jsongo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+//ErrorCycle error if a JSONNode contains itself, for instance after being attached to one of its own children
+var ErrorCycle = errors.New("jsongo: cycle detected")
+
+//identity return what makes two container JSONNode the same: the storage of their children
+//
+//return 0 for a JSONNode that cant be part of a cycle
+func (that *JSONNode) identity() uintptr {
+	switch that.t {
+	case TypeMap:
+		return reflect.ValueOf(that.m).Pointer()
+	case TypeArray:
+		if cap(that.a) == 0 {
+			return 0
+		}
+		//the address of the first element, reflect.ValueOf(that.a) would allocate to box the slice
+		return reflect.ValueOf(&that.a[:1][0]).Pointer()
+	}
+	return 0
+}
+
+//cycleError return ErrorCycle with the path where the cycle was found
+func cycleError(path []pathElem) error {
+	return fmt.Errorf("%w at %q", ErrorCycle, Path{elems: path}.String())
+}
+
+//Detach make that JSONNode the only owner of its children
+//
+//Every child is copied recursively so the subtree no longer share anything with another tree (values are not copied).
+//Use it after attaching a shared subtree with Copy that you intend to modify.
+//
+//Detach panic with ErrorCycle if that JSONNode contains itself
+func (that *JSONNode) Detach() *JSONNode {
+	detached, err := that.clone(nil, nil)
+	if err != nil {
+		panic(err)
+	}
+	*that = detached
+	return that
+}
+
+//clone return a recursive copy of that JSONNode
+func (that *JSONNode) clone(stack []uintptr, path []pathElem) (JSONNode, error) {
+	ret := *that
+	id := that.identity()
+	if id == 0 {
+		return ret, nil
+	}
+	for _, ancestor := range stack {
+		if ancestor == id {
+			return ret, cycleError(path)
+		}
+	}
+	stack = append(stack, id)
+	switch that.t {
+	case TypeMap:
+		ret.m = make(map[string]*JSONNode, len(that.m))
+		for key, child := range that.m {
+			c, err := child.clone(stack, append(path, pathElem{key: key, index: -1}))
+			if err != nil {
+				return ret, err
+			}
+			ret.m[key] = &c
+		}
+	case TypeArray:
+		ret.a = make([]*JSONNode, len(that.a))
+		for i := range that.a {
+			c, err := that.a[i].clone(stack, append(path, pathElem{index: i, isIndex: true}))
+			if err != nil {
+				return ret, err
+			}
+			ret.a[i] = &c
+		}
+	}
+	return ret, nil
+}
+
+//nodeHolders cache mayHoldNode for each type
+var nodeHolders sync.Map
+
+//mayHoldNode return true if a value of type rt can contain a *JSONNode that encoding/json would marshal
+func mayHoldNode(rt reflect.Type) bool {
+	if cached, ok := nodeHolders.Load(rt); ok {
+		return cached.(bool)
+	}
+	ret := typeHoldNode(rt, map[reflect.Type]bool{})
+	nodeHolders.Store(rt, ret)
+	return ret
+}
+
+func typeHoldNode(rt reflect.Type, visiting map[reflect.Type]bool) bool {
+	if rt == nodeType || rt.Kind() == reflect.Interface {
+		return true
+	}
+	if visiting[rt] || rt.Implements(marshalerType) || rt.Implements(textMarshalerType) {
+		return false
+	}
+	visiting[rt] = true
+	switch rt.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeHoldNode(rt.Elem(), visiting)
+	case reflect.Map:
+		return typeHoldNode(rt.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < rt.NumField(); i++ {
+			if rt.Field(i).IsExported() && typeHoldNode(rt.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//valueCycle return ErrorCycle if v hold a JSONNode leading back to one of the containers of stack
+//
+//The codec marshal the JSONNode held by a value with a new encodeState, which cant see the containers being encoded,
+//so the values are checked before being given to it. seen hold the pointers already walked
+func valueCycle(v reflect.Value, stack []uintptr, path []pathElem, seen map[uintptr]bool) error {
+	if !v.IsValid() || !mayHoldNode(v.Type()) {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type() == nodeType {
+			return v.Interface().(*JSONNode).nodeCycle(stack, path, seen)
+		}
+		if seen[v.Pointer()] {
+			return nil
+		}
+		seen[v.Pointer()] = true
+		return valueCycle(v.Elem(), stack, path, seen)
+	case reflect.Interface:
+		return valueCycle(v.Elem(), stack, path, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := valueCycle(v.Index(i), stack, path, seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := valueCycle(iter.Value(), stack, path, seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				if err := valueCycle(v.Field(i), stack, path, seen); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//nodeCycle return ErrorCycle if that JSONNode, or a JSONNode held by one of its values, lead back to one of the containers of stack
+func (that *JSONNode) nodeCycle(stack []uintptr, path []pathElem, seen map[uintptr]bool) error {
+	switch that.t {
+	case TypeValue:
+		if that.compute == nil {
+			return valueCycle(reflect.ValueOf(that.v), stack, path, seen)
+		}
+		return nil
+	case TypeMap, TypeArray:
+	default:
+		return nil
+	}
+	id := that.identity()
+	if id == 0 {
+		return nil
+	}
+	if slices.Contains(stack, id) {
+		return cycleError(path)
+	}
+	stack = append(slices.Clip(stack), id)
+	if that.t == TypeMap {
+		for _, key := range sortedKeys(that.m) {
+			if err := that.m[key].nodeCycle(stack, append(path, newKeyElem(key)), seen); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := range that.a {
+		if err := that.a[i].nodeCycle(stack, append(path, pathElem{index: i, isIndex: true}), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,7 +3,10 @@ package jsongo
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"unicode/utf8"
 )
 
 //DebugPrint Print a JSONNode as json withindent
@@ -54,7 +57,135 @@ func (that *JSONNode) DebugProspect(indentlevel int, indentchar string) {
 		that.debugProspectMap(indentlevel, indentchar)
 	case TypeArray:
 		that.debugProspectArray(indentlevel, indentchar)
+	case TypeNull:
+		printfindent(indentlevel, indentchar, "Is of Type: TypeNull\n")
 	case TypeUndefined:
 		printfindent(indentlevel, indentchar, "Is of Type: TypeUndefined\n")
 	}
 }
+
+//treePreviewLen is the maximum number of runes of a value shown by Tree
+const treePreviewLen = 40
+
+//Tree Print an indented tree view of a node and all its children to w
+//
+//Each line show the key or index of the node, its type, a preview of its value and its flags
+func (that *JSONNode) Tree(w io.Writer) {
+	fmt.Fprintf(w, "%s\n", that.treeLabel())
+	that.tree(w, "")
+}
+
+func (that *JSONNode) tree(w io.Writer, indent string) {
+	var labels []string
+	var children []*JSONNode
+	switch that.t {
+	case TypeMap:
+		for _, key := range sortedKeys(that.m) {
+			asJSON, _ := json.Marshal(key)
+			labels = append(labels, string(asJSON))
+			children = append(children, that.m[key])
+		}
+	case TypeArray:
+		for i := range that.a {
+			labels = append(labels, fmt.Sprintf("[%d]", i))
+			children = append(children, that.a[i])
+		}
+	}
+	for i, child := range children {
+		branch, next := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s %s\n", indent, branch, labels[i], child.treeLabel())
+		child.tree(w, indent+next)
+	}
+}
+
+//treeLabel return the type, value preview and flags of a node
+func (that *JSONNode) treeLabel() string {
+	var label string
+	switch that.t {
+	case TypeMap:
+		label = fmt.Sprintf("(map len=%d)", len(that.m))
+	case TypeArray:
+		label = fmt.Sprintf("(array len=%d)", len(that.a))
+	case TypeValue:
+		if that.compute != nil {
+			label = "(value computed)"
+			break
+		}
+		label = fmt.Sprintf("(value %T) %s", that.Get(), valuePreview(that.Get()))
+	case TypeNull:
+		label = "(null)"
+	default:
+		label = "(undefined)"
+	}
+	if flags := that.flags(); len(flags) > 0 {
+		label += " [" + strings.Join(flags, ",") + "]"
+	}
+	return label
+}
+
+//flags return the names of the flags set on a node
+func (that *JSONNode) flags() []string {
+	var flags []string
+	if that.dontExpand {
+		flags = append(flags, "dontExpand")
+	}
+	if that.frozen {
+		flags = append(flags, "frozen")
+	}
+	o := that.opts()
+	if o.lenient {
+		flags = append(flags, "lenient")
+	}
+	if o.required {
+		flags = append(flags, "required")
+	}
+	if o.copyValues {
+		flags = append(flags, "copyValues")
+	}
+	if o.numeric {
+		flags = append(flags, "numericKeys")
+	}
+	if o.keepEscapes {
+		flags = append(flags, "keepEscapes")
+	}
+	if that.link != nil {
+		flags = append(flags, "tracked")
+	}
+	if o.filter != nil && o.filter.only {
+		flags = append(flags, "generateOnly")
+	} else if o.filter != nil {
+		flags = append(flags, "generateExcept")
+	}
+	if o.intern != nil {
+		flags = append(flags, "intern")
+	}
+	if len(o.constrain) > 0 {
+		flags = append(flags, "constrained")
+	}
+	if len(o.enum) > 0 {
+		flags = append(flags, "enum")
+	}
+	if o.precision > 0 {
+		flags = append(flags, "floatPrecision")
+	}
+	for _, tag := range o.tags {
+		flags = append(flags, "tag:"+tag)
+	}
+	return flags
+}
+
+//valuePreview return a short json representation of a value
+func valuePreview(v interface{}) string {
+	asJSON, err := json.Marshal(v)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	if utf8.RuneCount(asJSON) <= treePreviewLen {
+		return string(asJSON)
+	}
+	runes := []rune(string(asJSON))
+	return string(runes[:treePreviewLen]) + "…"
+}
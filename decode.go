@@ -0,0 +1,223 @@
+package jsongo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+//decodeState hold what is needed while unmarshaling a tree
+type decodeState struct {
+	path        []pathElem      //path of the JSONNode being decoded
+	filter      *generateFilter //filter in effect, see GenerateOnly
+	filterBase  int             //length of path where the filter was set
+	intern      *InternPool     //pool in effect for the new keys, see UseInternPool
+	keepEscapes bool            //keep the input text of the strings, see KeepEscapes
+	ctx         context.Context //context of UnmarshalContext, nil otherwise
+	steps       int             //number of JSONNode decoded, see checkContext
+}
+
+//UnmarshalJSON Make JSONNode a Unmarshaler Interface compatible
+func (that *JSONNode) UnmarshalJSON(data []byte) error {
+	return that.unmarshal(data, &decodeState{})
+}
+
+//generate return true if a new child can be added at the current path
+func (d *decodeState) generate() bool {
+	return d.filter == nil || d.filter.allow(d.path[d.filterBase:])
+}
+
+func (that *JSONNode) unmarshal(data []byte, d *decodeState) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if that.frozen {
+		return ErrorFrozen
+	}
+	if err := d.checkContext(); err != nil {
+		return err
+	}
+	that.stamp()
+	if that.dontExpand && that.t == TypeUndefined {
+		return nil
+	}
+	if o := that.opts(); o.filter != nil {
+		filter, filterBase := d.filter, d.filterBase
+		d.filter, d.filterBase = o.filter, len(d.path)
+		defer func() { d.filter, d.filterBase = filter, filterBase }()
+	}
+	if o := that.opts(); o.intern != nil {
+		intern := d.intern
+		d.intern = o.intern
+		defer func() { d.intern = intern }()
+	}
+	if that.opts().keepEscapes && !d.keepEscapes {
+		d.keepEscapes = true
+		defer func() { d.keepEscapes = false }()
+	}
+	if isJSONNull(data) {
+		if that.t == TypeUndefined || that.t == TypeNull {
+			defer that.logChange(that.snapshot())
+			that.t = TypeNull
+			return nil
+		}
+	} else if that.t == TypeNull {
+		if that.dontExpand {
+			return ErrorTypeUnmarshaling
+		}
+		that.t = TypeUndefined
+	}
+	if that.t == TypeValue {
+		return that.unmarshalValue(data, d)
+	}
+	if data[0] == '{' {
+		if that.t != TypeMap && that.t != TypeUndefined {
+			return ErrorTypeUnmarshaling
+		}
+		return that.unmarshalMap(data, d)
+	}
+	if data[0] == '[' {
+		if that.t != TypeArray && that.t != TypeUndefined {
+			return ErrorTypeUnmarshaling
+		}
+		return that.unmarshalArray(data, d)
+
+	}
+	if that.t == TypeUndefined {
+		return that.unmarshalValue(data, d)
+	}
+	return ErrorTypeUnmarshaling
+}
+
+func (that *JSONNode) unmarshalMap(data []byte, d *decodeState) error {
+	tmp := make(map[string]json.RawMessage)
+	err := GetCodec().Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	for k := range tmp {
+		d.path = append(d.path, newKeyElem(k))
+		if _, ok := that.m[k]; ok {
+			err = that.m[k].unmarshal(tmp[k], d)
+		} else if !that.dontExpand && d.generate() {
+			key := k
+			if d.intern != nil {
+				key = d.intern.Intern(k)
+			}
+			err = that.Map(key).unmarshal(tmp[k], d)
+		}
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	for _, k := range sortedKeys(that.m) {
+		if _, ok := tmp[k]; !ok && that.m[k].opts().required {
+			return d.pathError(fmt.Errorf("%w %q", ErrorRequired, k))
+		}
+	}
+	return nil
+}
+
+func (that *JSONNode) unmarshalArray(data []byte, d *decodeState) error {
+	var tmp []json.RawMessage
+	err := GetCodec().Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	for i := len(tmp) - 1; i >= 0; i-- {
+		d.path = append(d.path, pathElem{index: i, isIndex: true})
+		if i < len(that.a) {
+			err = that.a[i].unmarshal(tmp[i], d)
+		} else if !that.dontExpand && d.generate() {
+			err = that.At(i).unmarshal(tmp[i], d)
+		}
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	for i := len(tmp); i < len(that.a); i++ {
+		if that.a[i].opts().required {
+			return d.pathError(fmt.Errorf("%w [%d]", ErrorRequired, i))
+		}
+	}
+	return nil
+}
+
+func (that *JSONNode) unmarshalValue(data []byte, d *decodeState) error {
+	defer that.logChange(that.snapshot())
+	if that.v != nil {
+		if o := that.opts(); len(o.constrain) == 0 && len(o.enum) == 0 {
+			if err := that.decodeValue(data, that.v); err != nil {
+				return err
+			}
+			that.unfilled = false
+			that.keepSource(data, d)
+			return nil
+		}
+		rv := reflect.ValueOf(that.v)
+		tmp := reflect.New(rv.Type().Elem())
+		tmp.Elem().Set(rv.Elem())
+		if err := that.decodeValue(data, tmp.Interface()); err != nil {
+			return err
+		}
+		val := tmp.Interface()
+		if that.vChanged {
+			val = tmp.Elem().Interface()
+		}
+		if err := that.check(val); err != nil {
+			return d.pathError(err)
+		}
+		rv.Elem().Set(tmp.Elem())
+		that.unfilled = false
+		that.keepSource(data, d)
+		return nil
+	}
+	var tmp interface{}
+	err := GetCodec().Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if f, ok := tmp.(float64); ok {
+		tmp = preciseInt(data, f)
+	}
+	if err := that.check(tmp); err != nil {
+		return d.pathError(err)
+	}
+	that.setVal(tmp)
+	that.keepSource(data, d)
+	return nil
+}
+
+//decodeValue unmarshal data in target, coercing it if that JSONNode is lenient
+//
+//Numbers decoded in an interface{} keep their precision like in a new JSONNode, see preciseInt
+func (that *JSONNode) decodeValue(data []byte, target interface{}) error {
+	err := GetCodec().Unmarshal(data, target)
+	if err != nil && that.opts().lenient {
+		if coerced, ok := coerce(data, reflect.TypeOf(target).Elem()); ok {
+			data = coerced
+			err = GetCodec().Unmarshal(data, target)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if p, ok := target.(*interface{}); ok {
+		if f, ok := (*p).(float64); ok {
+			*p = preciseInt(data, f)
+		}
+	}
+	return nil
+}
+
+//pathError add the current path to err
+func (d *decodeState) pathError(err error) error {
+	return fmt.Errorf("%w at %q", err, Path{elems: d.path}.String())
+}
+
+func isJSONNull(data []byte) bool {
+	return len(data) == 4 && string(data) == "null"
+}
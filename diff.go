@@ -0,0 +1,226 @@
+package jsongo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+)
+
+//ChangeKind tell what happened to a path between the two trees of a Diff
+type ChangeKind int
+
+const (
+	//ChangeAdded the path only exists in the new tree
+	ChangeAdded ChangeKind = iota
+	//ChangeRemoved the path only exists in the old tree
+	ChangeRemoved
+	//ChangeModified the path exists in both trees with different values or types
+	ChangeModified
+)
+
+//Change is a difference found by Diff
+type Change struct {
+	Path Path
+	Kind ChangeKind
+	Old  *JSONNode //nil for ChangeAdded
+	New  *JSONNode //nil for ChangeRemoved
+}
+
+//DiffResult is the list of changes between two trees, ordered by path
+type DiffResult struct {
+	Changes []Change
+}
+
+//Diff Return the structural differences between from and to
+//
+//Maps are compared key by key and arrays index by index, values are compared by their canonical form (see MarshalCanonical)
+//so 1 and 1.0 are equal. A TypeUndefined is equal to a TypeNull
+func Diff(from, to *JSONNode) *DiffResult {
+	d := &DiffResult{}
+	d.diff(nil, from, to)
+	return d
+}
+
+//Equal Return true if there is no change
+func (d *DiffResult) Equal() bool {
+	return len(d.Changes) == 0
+}
+
+func (d *DiffResult) diff(path []pathElem, from, to *JSONNode) {
+	switch {
+	case from.t == TypeMap && to.t == TypeMap:
+		keys := sortedKeys(from.m)
+		for key := range to.m {
+			if _, ok := from.m[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			elem := append(path, newKeyElem(key))
+			fromChild, inFrom := from.m[key]
+			toChild, inTo := to.m[key]
+			switch {
+			case !inTo:
+				d.add(elem, ChangeRemoved, fromChild, nil)
+			case !inFrom:
+				d.add(elem, ChangeAdded, nil, toChild)
+			default:
+				d.diff(elem, fromChild, toChild)
+			}
+		}
+	case from.t == TypeArray && to.t == TypeArray:
+		for i := 0; i < len(from.a) || i < len(to.a); i++ {
+			elem := append(path, pathElem{index: i, isIndex: true})
+			switch {
+			case i >= len(to.a):
+				d.add(elem, ChangeRemoved, from.a[i], nil)
+			case i >= len(from.a):
+				d.add(elem, ChangeAdded, nil, to.a[i])
+			default:
+				d.diff(elem, from.a[i], to.a[i])
+			}
+		}
+	case !sameValue(from, to):
+		d.add(path, ChangeModified, from, to)
+	}
+}
+
+func (d *DiffResult) add(path []pathElem, kind ChangeKind, from, to *JSONNode) {
+	d.Changes = append(d.Changes, Change{Path: Path{elems: append([]pathElem(nil), path...)}, Kind: kind, Old: from, New: to})
+}
+
+//sameValue compare two JSONNodes which are not both TypeMap or both TypeArray
+func sameValue(a, b *JSONNode) bool {
+	if a.t == TypeMap || a.t == TypeArray || b.t == TypeMap || b.t == TypeArray {
+		return false
+	}
+	ca, errA := a.MarshalCanonical()
+	cb, errB := b.MarshalCanonical()
+	return errA == nil && errB == nil && bytes.Equal(ca, cb)
+}
+
+//DiffFormat choose how DiffResult.Format render the changes
+type DiffFormat int
+
+const (
+	//FormatUnified write each path followed by its old value prefixed by - and its new value prefixed by +
+	FormatUnified DiffFormat = iota
+	//FormatSideBySide write one line per change with the path, the old value and the new value in columns
+	FormatSideBySide
+	//FormatTerse write one line per change with only its kind (+, - or ~) and its path
+	FormatTerse
+
+	//FormatColor can be combined with the other formats to colorize the output with ANSI escape codes
+	FormatColor DiffFormat = 1 << 8
+)
+
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+)
+
+//Format write the changes to w in a human readable format
+func (d *DiffResult) Format(w io.Writer, format DiffFormat) error {
+	p := &diffPrinter{color: format&FormatColor != 0}
+	switch format &^ FormatColor {
+	case FormatSideBySide:
+		p.sideBySide(d.Changes)
+	case FormatTerse:
+		p.terse(d.Changes)
+	default:
+		p.unified(d.Changes)
+	}
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+//diffPrinter render changes in a buffer
+type diffPrinter struct {
+	buf   bytes.Buffer
+	color bool
+}
+
+func (p *diffPrinter) unified(changes []Change) {
+	for _, c := range changes {
+		p.write(colorCyan, "@ "+pathLabel(c.Path))
+		p.buf.WriteByte('\n')
+		if c.Old != nil {
+			p.write(colorRed, "- "+diffValue(c.Old))
+			p.buf.WriteByte('\n')
+		}
+		if c.New != nil {
+			p.write(colorGreen, "+ "+diffValue(c.New))
+			p.buf.WriteByte('\n')
+		}
+	}
+}
+
+func (p *diffPrinter) sideBySide(changes []Change) {
+	pathWidth, oldWidth := 0, 0
+	for _, c := range changes {
+		pathWidth = max(pathWidth, len(pathLabel(c.Path)))
+		if c.Old != nil {
+			oldWidth = max(oldWidth, len(diffValue(c.Old)))
+		}
+	}
+	for _, c := range changes {
+		before, after := "", ""
+		if c.Old != nil {
+			before = diffValue(c.Old)
+		}
+		if c.New != nil {
+			after = diffValue(c.New)
+		}
+		p.write(colorCyan, fmt.Sprintf("%-*s", pathWidth, pathLabel(c.Path)))
+		p.buf.WriteString(" | ")
+		p.write(colorRed, fmt.Sprintf("%-*s", oldWidth, before))
+		p.buf.WriteString(" | ")
+		p.write(colorGreen, after)
+		p.buf.WriteByte('\n')
+	}
+}
+
+func (p *diffPrinter) terse(changes []Change) {
+	for _, c := range changes {
+		switch c.Kind {
+		case ChangeAdded:
+			p.write(colorGreen, "+ "+pathLabel(c.Path))
+		case ChangeRemoved:
+			p.write(colorRed, "- "+pathLabel(c.Path))
+		default:
+			p.write(colorYellow, "~ "+pathLabel(c.Path))
+		}
+		p.buf.WriteByte('\n')
+	}
+}
+
+//write add s to the buffer, in color if colors are enabled
+func (p *diffPrinter) write(color, s string) {
+	if p.color {
+		p.buf.WriteString(color + s + colorReset)
+		return
+	}
+	p.buf.WriteString(s)
+}
+
+//pathLabel return the label of a path, "." for the root
+func pathLabel(path Path) string {
+	if path.Len() == 0 {
+		return "."
+	}
+	return path.String()
+}
+
+//diffValue return the compact json of node
+func diffValue(node *JSONNode) string {
+	data, err := node.MarshalJSON()
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	return string(data)
+}
@@ -0,0 +1,26 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffOrder(t *testing.T) {
+	var from, to JSONNode
+	if err := json.Unmarshal([]byte(`{"a":1,"c":{"x":1,"z":1},"d":[1]}`), &from); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"b":1,"a":2,"c":{"y":1,"z":2},"d":[1,2]}`), &to); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a", "b", "c.x", "c.y", "c.z", "d[1]"}
+	changes := Diff(&from, &to).Changes
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, change := range changes {
+		if p := change.Path.String(); p != want[i] {
+			t.Errorf("change %d is at %s, want %s", i, p, want[i])
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package jsongo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+//encodeState hold what is needed while marshaling a tree
+type encodeState struct {
+	buf    []byte
+	root   *JSONNode        //JSONNode on which the marshaling started
+	stack  []uintptr        //identity of the containers being encoded, to detect cycles
+	path   []pathElem       //path of the JSONNode being encoded, to report cycles
+	opts   MarshalOptions   //options of MarshalWith
+	keyBuf []string         //sorted keys of the maps being encoded, see keys
+	limits *TruncateOptions //limits of MarshalTruncated
+	//canonical rewrite the user values in their canonical form as soon as they are written, see MarshalCanonical
+	canonical bool
+}
+
+//MarshalOptions are the options of MarshalWith, the zero value marshal like MarshalJSON
+type MarshalOptions struct {
+	FloatPrecision int                    //decimal places of the floats without FloatPrecision set on their JSONNode, ignored if <= 0
+	NoExponent     bool                   //never write floats in scientific notation
+	NonFinite      NonFinitePolicy        //how NaN and ±Inf are written
+	KeyLess        func(a, b string) bool //order of the keys of maps, lexicographic if nil, see NaturalLess
+	PinnedKeys     []string               //keys written first in this order, before the ones ordered by KeyLess
+	//ASCIIOnly write every non ASCII character of the strings as \uXXXX
+	ASCIIOnly bool
+	//PreserveEscapes write the string values as they were written in the input of Unmarshal, with the same escapes,
+	//as long as they were not modified since. Unmarshal must have been told to keep them, see KeepEscapes.
+	//Keys and strings set with Val are written as usual
+	PreserveEscapes bool
+	//NormalizeString is called on every key and string value before writing it, for example with norm.NFC.String
+	//from golang.org/x/text/unicode/norm so that strings equal for a human are written with the same bytes
+	NormalizeString func(string) string
+}
+
+//MarshalJSON Make JSONNode a Marshaler Interface compatible
+func (that *JSONNode) MarshalJSON() ([]byte, error) {
+	return that.MarshalWith(MarshalOptions{})
+}
+
+//MarshalWith Return the json encoding of that JSONNode using opts
+func (that *JSONNode) MarshalWith(opts MarshalOptions) ([]byte, error) {
+	e := &encodeState{root: that, opts: opts}
+	if err := e.encode(that); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+func (e *encodeState) encode(node *JSONNode) error {
+	if id := node.identity(); id != 0 {
+		for _, ancestor := range e.stack {
+			if ancestor == id {
+				return cycleError(e.path)
+			}
+		}
+		e.stack = append(e.stack, id)
+		defer func() { e.stack = e.stack[:len(e.stack)-1] }()
+	}
+	if e.limits != nil && e.tooDeep(node) {
+		return e.encodeString(containerMarker(node))
+	}
+	switch node.t {
+	case TypeMap:
+		e.buf = append(e.buf, '{')
+		keys := e.keys(node.m)
+		defer e.releaseKeys(len(node.m))
+		for i, key := range keys {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if e.limits != nil && i == e.limits.MaxItems {
+				if err := e.encodeMarkerKey(len(keys) - i); err != nil {
+					return err
+				}
+				break
+			}
+			if err := e.encodeString(key); err != nil {
+				return err
+			}
+			e.buf = append(e.buf, ':')
+			e.path = append(e.path, pathElem{key: key, index: -1})
+			if err := e.encode(node.m[key]); err != nil {
+				return err
+			}
+			e.path = e.path[:len(e.path)-1]
+		}
+		e.buf = append(e.buf, '}')
+	case TypeArray:
+		e.buf = append(e.buf, '[')
+		for i := range node.a {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if e.limits != nil && i == e.limits.MaxItems {
+				if err := e.encodeString(fmt.Sprintf("…(+%d items)", len(node.a)-i)); err != nil {
+					return err
+				}
+				break
+			}
+			e.path = append(e.path, pathElem{index: i, isIndex: true})
+			if err := e.encode(node.a[i]); err != nil {
+				return err
+			}
+			e.path = e.path[:len(e.path)-1]
+		}
+		e.buf = append(e.buf, ']')
+	case TypeValue:
+		v := node.v
+		if node.compute != nil {
+			v = node.compute(e.root)
+		}
+		if f, bits, ok := floatValue(v); ok {
+			start := len(e.buf)
+			if err := e.encodeFloat(f, bits, e.floatPrecision(node)); err != nil || !e.canonical {
+				return err
+			}
+			return e.canonicalize(start)
+		}
+		if str, ok := stringValue(v); ok {
+			if e.limits != nil {
+				str = truncateString(str, e.limits.MaxString)
+			} else if src, ok := e.preserved(node, str); ok {
+				e.buf = append(e.buf, src...)
+				return nil
+			}
+			return e.encodeString(str)
+		}
+		return e.encodeValue(v)
+	default:
+		e.buf = append(e.buf, "null"...)
+	}
+	return nil
+}
+
+//encodeString encode a map key or a string value
+func (e *encodeState) encodeString(s string) error {
+	if e.opts.stringOptions() {
+		e.appendText(s)
+		return nil
+	}
+	if _, ok := GetCodec().(StdCodec); ok || e.canonical {
+		e.buf = appendString(e.buf, s)
+		return nil
+	}
+	return e.encodeValue(s)
+}
+
+//encodeValue encode a user value, a JSONNode used as a value is encoded with the same encodeState
+func (e *encodeState) encodeValue(v interface{}) error {
+	if node, ok := v.(*JSONNode); ok && node != nil {
+		return e.encode(node)
+	}
+	start := len(e.buf)
+	if err := e.appendValue(v); err != nil || !e.canonical {
+		return err
+	}
+	return e.canonicalize(start)
+}
+
+//appendValue encode a user value with the Codec
+func (e *encodeState) appendValue(v interface{}) error {
+	if _, ok := GetCodec().(StdCodec); ok {
+		var done bool
+		if e.buf, done = appendScalar(e.buf, v); done {
+			return nil
+		}
+	}
+	if err := valueCycle(reflect.ValueOf(v), e.stack, e.path, map[uintptr]bool{}); err != nil {
+		return err
+	}
+	b, err := GetCodec().Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf = append(e.buf, b...)
+	return nil
+}
@@ -0,0 +1,103 @@
+package jsongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//stringOptions return true if the strings must be written with appendText
+func (opts *MarshalOptions) stringOptions() bool {
+	return opts.ASCIIOnly || opts.NormalizeString != nil
+}
+
+//appendText append s quoted following the ASCIIOnly and NormalizeString options
+func (e *encodeState) appendText(s string) {
+	if e.opts.NormalizeString != nil {
+		s = e.opts.NormalizeString(s)
+	}
+	if e.opts.ASCIIOnly {
+		e.buf = appendASCIIString(e.buf, s)
+	} else {
+		e.buf = appendString(e.buf, s)
+	}
+}
+
+//appendASCIIString is appendString writing every non ASCII character as \uXXXX, with surrogate pairs above U+FFFF
+func appendASCIIString(dst []byte, s string) []byte {
+	start := len(dst)
+	dst = appendString(dst, s)
+	if isASCII(dst[start:]) {
+		return dst
+	}
+	quoted := string(dst[start:])
+	dst = dst[:start]
+	var units []uint16
+	for _, r := range quoted {
+		if r < utf8.RuneSelf {
+			dst = append(dst, byte(r))
+			continue
+		}
+		units = utf16.AppendRune(units[:0], r)
+		for _, u := range units {
+			dst = append(dst, '\\', 'u', hexDigits[u>>12], hexDigits[u>>8&0xF], hexDigits[u>>4&0xF], hexDigits[u&0xF])
+		}
+	}
+	return dst
+}
+
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+//KeepEscapes set or not if Unmarshal keep the input text of the strings written with escapes under that JSONNode
+//
+//MarshalOptions.PreserveEscapes can then write them with the same escapes. Unmarshal keep nothing by default,
+//as it cost a copy of every such string
+func (that *JSONNode) KeepEscapes(val bool) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.keepEscapes = val })
+	return that
+}
+
+//keepSource remember data, the input text of that JSONNode value, if it is a string written with escapes and d keep them
+func (that *JSONNode) keepSource(data []byte, d *decodeState) {
+	if d.keepEscapes && len(data) > 0 && data[0] == '"' && bytes.IndexByte(data, '\\') >= 0 {
+		src := bytes.Clone(data)
+		that.setOptions(func(o *nodeOptions) { o.src = src })
+		return
+	}
+	that.dropSource()
+}
+
+//dropSource forget the input text kept by keepSource, once the value of that JSONNode changed
+func (that *JSONNode) dropSource() {
+	if that.opts().src != nil {
+		that.setOptions(func(o *nodeOptions) { o.src = nil })
+	}
+}
+
+//preserved return the input text of the string str held by node if PreserveEscapes can use it
+func (e *encodeState) preserved(node *JSONNode, str string) ([]byte, bool) {
+	src := node.opts().src
+	if !e.opts.PreserveEscapes || src == nil || node.compute != nil {
+		return nil, false
+	}
+	if e.opts.ASCIIOnly && !isASCII(src) {
+		return nil, false
+	}
+	if e.opts.NormalizeString != nil && e.opts.NormalizeString(str) != str {
+		return nil, false
+	}
+	//the value may have been changed through a pointer given to Val since it was unmarshaled
+	var current string
+	if json.Unmarshal(src, &current) != nil || current != str {
+		return nil, false
+	}
+	return src, true
+}
@@ -0,0 +1,128 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//WatchInterval is how often a Watcher check its file
+var WatchInterval = time.Second
+
+//LoadFile Unmarshal the json file at path in a new JSONNode
+func LoadFile(path string) (*JSONNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	node := &JSONNode{}
+	if err := json.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+//SaveFile write that JSONNode indented in the file at path, creating it with perm if needed
+func (that *JSONNode) SaveFile(path string, perm os.FileMode) error {
+	data, err := json.MarshalIndent(that, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), perm)
+}
+
+//Watcher keep a frozen snapshot of a json file up to date, see Watch
+type Watcher struct {
+	path     string
+	onChange func(*JSONNode)
+	current  atomic.Pointer[JSONNode]
+	mu       sync.Mutex
+	err      error     //error of the last reload
+	modTime  time.Time //modification time of the file when it was last loaded
+	size     int64     //size of the file when it was last loaded
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+//Watch load the json file at path and reload it when it changes
+//
+//Each version of the file is a new frozen JSONNode, swapped atomically so Current can be used from any goroutine.
+//onChange, which can be nil, is called with every new version after the first one.
+//The file is checked every WatchInterval, a version that cannot be loaded is skipped and reported by Err.
+//
+//Watch return an error if the file cannot be loaded the first time
+func Watch(path string, onChange func(*JSONNode)) (*Watcher, error) {
+	w := &Watcher{path: path, onChange: onChange, stop: make(chan struct{}), done: make(chan struct{})}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	node, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	w.current.Store(node.Freeze())
+	go w.run()
+	return w, nil
+}
+
+//Current Return the last version of the file
+func (w *Watcher) Current() *JSONNode {
+	return w.current.Load()
+}
+
+//Err Return the error of the last reload, nil if it succeeded
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+//Close stop watching the file, Current keeps returning the last version
+//
+//Close can be called more than once and from several goroutines
+func (w *Watcher) Close() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.reload()
+		}
+	}
+}
+
+//reload load the file again if it changed since the last load
+func (w *Watcher) reload() {
+	info, err := os.Stat(w.path)
+	if err == nil && info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return
+	}
+	var node *JSONNode
+	if err == nil {
+		node, err = LoadFile(w.path)
+	}
+	w.mu.Lock()
+	w.err = err
+	w.mu.Unlock()
+	if err != nil {
+		return
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	w.current.Store(node.Freeze())
+	if w.onChange != nil {
+		w.onChange(node)
+	}
+}
@@ -0,0 +1,23 @@
+package jsongo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWatcherCloseTwice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(path, []byte(`{"a":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Watch(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	w.Close()
+	if w.Current().At("a").Get() == nil {
+		t.Fatal("the last version was lost")
+	}
+}
@@ -0,0 +1,206 @@
+package jsongo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+)
+
+//ErrorFill error if Fill cannot generate a value accepted by the Enum and the constraints of a JSONNode
+var ErrorFill = errors.New("jsongo: Fill: cannot generate an accepted value")
+
+//FillOptions are the options of Fill, the zero value generate numbers between 0 and 1000 and strings of 5 to 12 letters
+type FillOptions struct {
+	Rand      *rand.Rand //source of the generated values, seed it to get the same tree each time, random if nil
+	MinNumber float64    //lower bound of the generated numbers
+	MaxNumber float64    //upper bound of the generated numbers, 1000 if MinNumber and MaxNumber are 0
+	MinLen    int        //minimum length of the generated strings
+	MaxLen    int        //maximum length of the generated strings, 12 if MinLen and MaxLen are 0 (MinLen being 5 then)
+	ArrayLen  int        //arrays with at least one element are grown to ArrayLen elements, built like the first one
+	Attempts  int        //number of values generated for a JSONNode before giving up with ErrorFill, 100 if 0
+}
+
+//Fill set every TypeValue under that JSONNode to a generated value, to build test payloads from a pre-built tree
+//
+//Each value is generated according to its current type: a random string, bool or number in the range of opts.
+//The values are picked in the Enum of their JSONNode if they have one, TypeUndefined JSONNode with an Enum are filled too.
+//Values which are not accepted by the constraints of their JSONNode are generated again, see FillOptions.Attempts.
+//Values set with a pointer are filled through it, computed values and values of other kinds (structs, slices...) are left as is.
+//
+//Use BuildFromSpec to get a tree from a spec, then Fill it
+func (that *JSONNode) Fill(opts FillOptions) error {
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	if opts.MinNumber == 0 && opts.MaxNumber == 0 {
+		opts.MaxNumber = 1000
+	}
+	if opts.MinLen == 0 && opts.MaxLen == 0 {
+		opts.MinLen, opts.MaxLen = 5, 12
+	}
+	if opts.Attempts <= 0 {
+		opts.Attempts = 100
+	}
+	f := &filler{opts: opts}
+	return f.fill(that, nil)
+}
+
+//filler hold what is needed by Fill
+type filler struct {
+	opts FillOptions
+}
+
+func (f *filler) fill(node *JSONNode, path []pathElem) error {
+	switch node.t {
+	case TypeMap:
+		for _, key := range sortedKeys(node.m) {
+			if err := f.fill(node.m[key], append(path, newKeyElem(key))); err != nil {
+				return err
+			}
+		}
+	case TypeArray:
+		if f.opts.ArrayLen > len(node.a) && len(node.a) > 0 {
+			if node.frozen {
+				return fmt.Errorf("%w at %q", ErrorFrozen, Path{elems: path}.String())
+			}
+			template := (&JSONNode{}).Copy(node.a[0], true)
+			from := len(node.a)
+			node.Resize(f.opts.ArrayLen)
+			for i := from; i < len(node.a); i++ {
+				node.a[i].Copy(template, true)
+				detachValues(node.a[i])
+			}
+		}
+		for i := range node.a {
+			if err := f.fill(node.a[i], append(path, pathElem{index: i, isIndex: true})); err != nil {
+				return err
+			}
+		}
+	case TypeValue:
+		if node.compute == nil {
+			return f.value(node, path)
+		}
+	case TypeUndefined:
+		if len(node.opts().enum) > 0 {
+			return f.value(node, path)
+		}
+	}
+	return nil
+}
+
+//value set a generated value in node
+func (f *filler) value(node *JSONNode, path []pathElem) error {
+	if node.frozen {
+		return fmt.Errorf("%w at %q", ErrorFrozen, Path{elems: path}.String())
+	}
+	if node.t == TypeUndefined {
+		enum := node.opts().enum
+		val := enum[f.opts.Rand.IntN(len(enum))]
+		if err := node.check(val); err != nil {
+			return fmt.Errorf("%w at %q: %w", ErrorFill, Path{elems: path}.String(), err)
+		}
+		node.ValNoCopy(val)
+		return nil
+	}
+	target := reflect.ValueOf(node.v).Elem()
+	var err error
+	for attempt := 0; attempt < f.opts.Attempts; attempt++ {
+		gen, ok := f.generate(node, target)
+		if !ok {
+			return nil
+		}
+		tmp := reflect.New(target.Type())
+		tmp.Elem().Set(gen)
+		val := tmp.Interface()
+		if node.vChanged {
+			val = gen
+		}
+		if err = node.check(val); err == nil {
+			node.mutate()
+			defer node.logChange(node.snapshot())
+			if node.vChanged {
+				//the storage of the value may be shared with a copy of that JSONNode
+				node.v = tmp.Interface()
+			} else {
+				target.Set(gen)
+			}
+			node.unfilled = false
+			node.dropSource()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w at %q: %w", ErrorFill, Path{elems: path}.String(), err)
+}
+
+//generate return a random value that can be stored in target, false if its kind is not supported
+func (f *filler) generate(node *JSONNode, target reflect.Value) (reflect.Value, bool) {
+	rt := target.Type()
+	if rt.Kind() == reflect.Interface {
+		if target.IsNil() {
+			if len(node.opts().enum) == 0 {
+				return reflect.Value{}, false
+			}
+		} else {
+			rt = target.Elem().Type()
+		}
+	}
+	if enum := node.opts().enum; len(enum) > 0 {
+		val := reflect.ValueOf(enum[f.opts.Rand.IntN(len(enum))])
+		switch {
+		case !val.IsValid():
+			return reflect.Zero(target.Type()), true
+		case val.Type().AssignableTo(target.Type()):
+			return val, true
+		case val.Type().ConvertibleTo(rt):
+			return val.Convert(rt), true
+		}
+		return reflect.Value{}, false
+	}
+	gen := reflect.New(rt).Elem()
+	r := f.opts.Rand
+	number := f.opts.MinNumber + r.Float64()*(f.opts.MaxNumber-f.opts.MinNumber)
+	switch rt.Kind() {
+	case reflect.String:
+		b := make([]byte, f.opts.MinLen+r.IntN(max(f.opts.MaxLen-f.opts.MinLen, 0)+1))
+		for i := range b {
+			b[i] = byte('a' + r.IntN(26))
+		}
+		gen.SetString(string(b))
+	case reflect.Bool:
+		gen.SetBool(r.IntN(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		limit := math.Ldexp(1, rt.Bits()-1)
+		gen.SetInt(int64(math.Max(-limit, math.Min(math.Floor(number), limit-1))))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		limit := math.Ldexp(1, rt.Bits())
+		gen.SetUint(uint64(math.Max(0, math.Min(math.Floor(number), limit-1))))
+	case reflect.Float32, reflect.Float64:
+		gen.SetFloat(number)
+	default:
+		return reflect.Value{}, false
+	}
+	return gen, true
+}
+
+//detachValues give their own storage to the values under node, which Copy shares with the copied JSONNode
+func detachValues(node *JSONNode) {
+	switch node.t {
+	case TypeMap:
+		for _, child := range node.m {
+			detachValues(child)
+		}
+	case TypeArray:
+		for i := range node.a {
+			detachValues(node.a[i])
+		}
+	case TypeValue:
+		rv := reflect.ValueOf(node.v)
+		if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			cp := reflect.New(rv.Type().Elem())
+			cp.Elem().Set(rv.Elem())
+			node.v = cp.Interface()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package jsongo
+
+//generateFilter decide which new paths Unmarshal can generate, see GenerateOnly and GenerateExcept
+type generateFilter struct {
+	patterns []*Query
+	only     bool //true for GenerateOnly, false for GenerateExcept
+}
+
+//GenerateOnly set which incoming paths Unmarshal will generate in that JSONNode
+//
+//patterns are relative to that JSONNode and use the CompileQuery syntax, like "items[*].id" or "meta".
+//A new node is generated only if its path leads to a pattern or is under it.
+//
+//Nodes which already exist are always filled, UnmarshalDontExpand still applies.
+//GenerateOnly panic with ErrorPathSyntax if a pattern cannot be parsed
+func (that *JSONNode) GenerateOnly(patterns ...string) *JSONNode {
+	filter := newGenerateFilter(patterns, true)
+	that.setOptions(func(o *nodeOptions) { o.filter = filter })
+	return that
+}
+
+//GenerateExcept set which incoming paths Unmarshal will not generate in that JSONNode
+//
+//patterns are relative to that JSONNode and use the CompileQuery syntax.
+//A new node is not generated if its path matches a pattern or is under it
+func (that *JSONNode) GenerateExcept(patterns ...string) *JSONNode {
+	filter := newGenerateFilter(patterns, false)
+	that.setOptions(func(o *nodeOptions) { o.filter = filter })
+	return that
+}
+
+//GenerateAll remove what GenerateOnly or GenerateExcept set on that JSONNode
+func (that *JSONNode) GenerateAll() *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.filter = nil })
+	return that
+}
+
+func newGenerateFilter(patterns []string, only bool) *generateFilter {
+	filter := &generateFilter{only: only, patterns: make([]*Query, len(patterns))}
+	for i := range patterns {
+		filter.patterns[i] = MustCompileQuery(patterns[i])
+	}
+	return filter
+}
+
+//allow return true if a node can be generated at path
+func (f *generateFilter) allow(path []pathElem) bool {
+	for _, pattern := range f.patterns {
+		switch matchElems(pattern.elems, path) {
+		case matchFull, matchUnder:
+			return f.only
+		case matchPrefix:
+			if f.only {
+				return true
+			}
+		}
+	}
+	return !f.only
+}
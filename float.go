@@ -0,0 +1,91 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+//NonFinitePolicy decide how MarshalWith write NaN and ±Inf, which have no json representation
+type NonFinitePolicy int
+
+const (
+	//NonFiniteError fail like encoding/json does
+	NonFiniteError NonFinitePolicy = iota
+	//NonFiniteNull write null
+	NonFiniteNull
+	//NonFiniteString write the strings "NaN", "+Inf" and "-Inf"
+	NonFiniteString
+)
+
+//FloatPrecision set the number of decimal places used to marshal the float value of that JSONNode
+//
+//0.1+0.2 is then written 0.30 with FloatPrecision(2) instead of 0.30000000000000004.
+//It takes precedence over MarshalOptions.FloatPrecision, a negative n remove it
+func (that *JSONNode) FloatPrecision(n int) *JSONNode {
+	if n < 0 {
+		n = -1
+	}
+	that.setOptions(func(o *nodeOptions) { o.precision = n + 1 })
+	return that
+}
+
+//floatValue return v as a float64 and its size in bits if v is a float32 or a float64 or a pointer to one
+func floatValue(v interface{}) (float64, int, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if rv.Type().Implements(marshalerType) || rv.Type().Implements(textMarshalerType) {
+			return 0, 0, false
+		}
+		return rv.Float(), rv.Type().Bits(), true
+	}
+	return 0, 0, false
+}
+
+//floatPrecision return the decimal places to use for the float value of node, -1 for the shortest representation
+func (e *encodeState) floatPrecision(node *JSONNode) int {
+	if precision := node.opts().precision; precision > 0 {
+		return precision - 1
+	}
+	if e.opts.FloatPrecision > 0 {
+		return e.opts.FloatPrecision
+	}
+	return -1
+}
+
+//encodeFloat encode f with precision decimal places, see floatPrecision
+func (e *encodeState) encodeFloat(f float64, bits int, precision int) error {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		switch e.opts.NonFinite {
+		case NonFiniteNull:
+			e.buf = append(e.buf, "null"...)
+		case NonFiniteString:
+			e.buf = strconv.AppendQuote(e.buf, nonFiniteString(f))
+		default:
+			return &json.UnsupportedValueError{Value: reflect.ValueOf(f), Str: strconv.FormatFloat(f, 'g', -1, bits)}
+		}
+	case precision >= 0:
+		e.buf = strconv.AppendFloat(e.buf, f, 'f', precision, bits)
+	case e.opts.NoExponent:
+		e.buf = strconv.AppendFloat(e.buf, f, 'f', -1, bits)
+	default:
+		e.buf = appendFloat(e.buf, f, bits)
+	}
+	return nil
+}
+
+func nonFiniteString(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return "NaN"
+}
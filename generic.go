@@ -0,0 +1,100 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+//ErrorGetType error if the value of a node cannot be converted to the requested type without loss
+var ErrorGetType = errors.New("jsongo: Get: value cannot be converted to the requested type")
+
+//Get Return the value of a TypeValue node as a T
+//
+//numbers are converted between numeric types as long as no precision is lost
+func Get[T any](n *JSONNode) (T, error) {
+	var zero T
+	if n.t != TypeValue {
+		return zero, ErrorRetrieveUserValue
+	}
+	v := n.Get()
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+	if num, ok := v.(json.Number); ok {
+		if i, err := num.Int64(); err == nil {
+			v = i
+		} else if u, err := strconv.ParseUint(string(num), 10, 64); err == nil {
+			v = u
+		} else if f, err := num.Float64(); err == nil {
+			v = f
+		}
+	}
+	rt := reflect.TypeOf(&zero).Elem()
+	rv := reflect.ValueOf(v)
+	if v == nil || !isNumberKind(rv.Kind()) || !isNumberKind(rt.Kind()) {
+		return zero, ErrorGetType
+	}
+	if signChange(rv, rt) {
+		return zero, ErrorGetType
+	}
+	converted := rv.Convert(rt)
+	if converted.Convert(rv.Type()).Interface() != rv.Interface() {
+		return zero, ErrorGetType
+	}
+	return converted.Interface().(T), nil
+}
+
+//GetPath Return the value of the TypeValue node at path as a T
+//
+//path looks like "a.b[0].c", nothing is built if the path does not exist
+func GetPath[T any](root *JSONNode, path string) (T, error) {
+	node, err := root.Lookup(path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Get[T](node)
+}
+
+//signChange return true if the number rv would change of sign once converted to rt, which the round trip of Get cant see
+func signChange(rv reflect.Value, rt reflect.Type) bool {
+	switch {
+	case isUnsignedKind(rt.Kind()) && !isUnsignedKind(rv.Kind()):
+		if rv.CanInt() {
+			return rv.Int() < 0
+		}
+		return rv.Float() < 0
+	case isUnsignedKind(rv.Kind()) && rt.Kind() >= reflect.Int && rt.Kind() <= reflect.Int64:
+		return rv.Uint() > uint64(1)<<(rt.Bits()-1)-1
+	}
+	return false
+}
+
+func isUnsignedKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+//toFloat64 return v as a float64 if v is a number
+func toFloat64(v interface{}) (float64, bool) {
+	if num, ok := v.(json.Number); ok {
+		f, err := num.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	if v == nil || !isNumberKind(rv.Kind()) {
+		return 0, false
+	}
+	return rv.Convert(reflect.TypeOf(float64(0))).Float(), true
+}
@@ -0,0 +1,52 @@
+package jsongo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestGetConversions(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		get  func(*JSONNode) (interface{}, error)
+		want interface{}
+	}{
+		{"int to uint64", 42, getAs[uint64], uint64(42)},
+		{"negative to uint64", -1, getAs[uint64], nil},
+		{"negative to uint8", -1, getAs[uint8], nil},
+		{"negative int64 to uint", int64(-5), getAs[uint], nil},
+		{"negative float to uint32", -1.0, getAs[uint32], nil},
+		{"uint64 above MaxInt64 to int64", uint64(1) << 63, getAs[int64], nil},
+		{"uint64 above MaxInt64 to int", uint64(math.MaxUint64), getAs[int], nil},
+		{"uint8 above MaxInt8 to int8", uint8(200), getAs[int8], nil},
+		{"uint64 MaxInt64 to int64", uint64(math.MaxInt64), getAs[int64], int64(math.MaxInt64)},
+		{"overflow int8", 300, getAs[int8], nil},
+		{"overflow uint8", 256, getAs[uint8], nil},
+		{"overflow int32", int64(math.MaxInt32) + 1, getAs[int32], nil},
+		{"fraction to int", 1.5, getAs[int], nil},
+		{"float to int", 3.0, getAs[int], 3},
+		{"huge float to int64", 1e30, getAs[int64], nil},
+		{"string", "a", getAs[string], "a"},
+		{"string to int", "1", getAs[int], nil},
+	}
+	for _, test := range tests {
+		var node JSONNode
+		node.Val(test.val)
+		got, err := test.get(&node)
+		if test.want == nil {
+			if !errors.Is(err, ErrorGetType) {
+				t.Errorf("%s: got %v, %v, want ErrorGetType", test.name, got, err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%s: got %v, %v, want %v", test.name, got, err, test.want)
+		}
+	}
+}
+
+func getAs[T any](node *JSONNode) (interface{}, error) {
+	return Get[T](node)
+}
@@ -0,0 +1,353 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//gjsonComp is one component of a gjson path
+type gjsonComp struct {
+	key      string
+	literal  bool        //true if key was escaped and cant be a pattern
+	forceKey bool        //true if key was prefixed by ':' and cant be an index (sjson syntax)
+	count    bool        //"#" component
+	query    *gjsonQuery //"#(...)" or "#(...)#" component
+}
+
+//gjsonQuery is the condition of a "#(...)" component
+type gjsonQuery struct {
+	path  []gjsonComp //value to test in each element, empty to test the element itself
+	op    string      //empty to test existence
+	value interface{}
+	all   bool //"#(...)#" return every match instead of the first one
+}
+
+//GetGJSON Return the JSONNode at a gjson style path, nothing is built in that JSONNode
+//
+//Supported syntax: "a.b.0" keys and indexes, '*' and '?' wildcards in keys, "items.#" array length,
+//"items.#.name" every name of items, "friends.#(last==\"Murphy\").first" first match
+//and "friends.#(age>45)#.last" every match (==, !=, <, <=, >, >=, % and !% are supported)
+//
+//Results which are not part of the tree (lengths and lists of matches) are returned as new JSONNode sharing their children with that JSONNode
+func (that *JSONNode) GetGJSON(path string) (*JSONNode, error) {
+	comps, err := parseGJSON(path)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := gjsonGet(that, comps)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrorPathNotFound, path)
+	}
+	return node, nil
+}
+
+//SetGJSON Set val at a sjson style path, building it like At would
+//
+//Numeric components are indexes unless the node is already a TypeMap or the component start with ':',
+//"-1" append a new element to a TypeArray
+//
+//The errors Val would panic with (ErrorFrozen, ErrorMultipleType, ErrorConstraint) are returned with the path,
+//nothing is built or set then
+func (that *JSONNode) SetGJSON(path string, val interface{}) error {
+	comps, err := parseGJSON(path)
+	if err != nil {
+		return err
+	}
+	keys := make([]interface{}, len(comps))
+	cur := that
+	for i, comp := range comps {
+		if comp.count || comp.query != nil {
+			return fmt.Errorf("%w: %q cant be used to set a value", ErrorPathSyntax, path)
+		}
+		keys[i] = comp.key
+		index, err := strconv.Atoi(comp.key)
+		if err == nil && (index >= 0 || index == Append) && !comp.forceKey && (cur == nil || cur.t != TypeMap) {
+			keys[i] = index
+		}
+		if cur != nil {
+			cur, _ = cur.find([]pathElem{newKeyElem(comp.key)})
+		}
+	}
+	node, err := that.TryAt(keys...)
+	if err != nil {
+		return err
+	}
+	if err := node.canVal(val); err != nil {
+		return fmt.Errorf("%w at %q", err, path)
+	}
+	node.Val(val)
+	return nil
+}
+
+func gjsonGet(cur *JSONNode, comps []gjsonComp) (*JSONNode, bool) {
+	if len(comps) == 0 {
+		return cur, true
+	}
+	comp := comps[0]
+	switch {
+	case comp.count:
+		if cur.t != TypeArray {
+			return nil, false
+		}
+		if len(comps) == 1 {
+			ret := &JSONNode{}
+			ret.Val(len(cur.a))
+			return ret, true
+		}
+		ret := (&JSONNode{}).SetType(TypeArray)
+		for i := range cur.a {
+			if found, ok := gjsonGet(cur.a[i], comps[1:]); ok {
+				ret.At(Append).Copy(found, false)
+			}
+		}
+		return ret, true
+	case comp.query != nil:
+		if cur.t != TypeArray {
+			return nil, false
+		}
+		var ret *JSONNode
+		if comp.query.all {
+			ret = (&JSONNode{}).SetType(TypeArray)
+		}
+		for i := range cur.a {
+			if !comp.query.match(cur.a[i]) {
+				continue
+			}
+			if !comp.query.all {
+				return gjsonGet(cur.a[i], comps[1:])
+			}
+			if found, ok := gjsonGet(cur.a[i], comps[1:]); ok {
+				ret.At(Append).Copy(found, false)
+			}
+		}
+		return ret, ret != nil
+	}
+	switch cur.t {
+	case TypeMap:
+		if next, ok := cur.m[comp.key]; ok {
+			return gjsonGet(next, comps[1:])
+		}
+		if comp.literal || !strings.ContainsAny(comp.key, "*?") {
+			return nil, false
+		}
+		for _, key := range sortedKeys(cur.m) {
+			if globMatch(comp.key, key) {
+				return gjsonGet(cur.m[key], comps[1:])
+			}
+		}
+	case TypeArray:
+		index, err := strconv.Atoi(comp.key)
+		if err == nil && index >= 0 && index < len(cur.a) {
+			return gjsonGet(cur.a[index], comps[1:])
+		}
+	}
+	return nil, false
+}
+
+//match return true if elem fulfill the query condition
+func (q *gjsonQuery) match(elem *JSONNode) bool {
+	node, ok := gjsonGet(elem, q.path)
+	if !ok {
+		return false
+	}
+	if q.op == "" {
+		return true
+	}
+	var v interface{}
+	switch node.t {
+	case TypeValue:
+		v = node.Get()
+	case TypeNull:
+	default:
+		return false
+	}
+	switch lit := q.value.(type) {
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return q.op == "!="
+		}
+		switch q.op {
+		case "%":
+			return globMatch(lit, s)
+		case "!%":
+			return !globMatch(lit, s)
+		}
+		return compareOp(q.op, strings.Compare(s, lit))
+	case float64:
+		f, ok := toFloat64(v)
+		if !ok {
+			return q.op == "!="
+		}
+		switch {
+		case f < lit:
+			return compareOp(q.op, -1)
+		case f > lit:
+			return compareOp(q.op, 1)
+		}
+		return compareOp(q.op, 0)
+	default:
+		if q.op != "==" && q.op != "!=" {
+			return false
+		}
+		return (v == q.value) == (q.op == "==")
+	}
+}
+
+func compareOp(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+//globMatch match s against a pattern where '*' match any sequence and '?' any character
+func globMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if globMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
+
+//parseGJSON split a gjson path in components
+func parseGJSON(path string) ([]gjsonComp, error) {
+	var comps []gjsonComp
+	for i := 0; i < len(path); i++ {
+		if strings.HasPrefix(path[i:], "#(") {
+			end := gjsonQueryEnd(path, i+2)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+			q, err := parseGJSONQuery(path[i+2 : end])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+			i = end + 1
+			if i < len(path) && path[i] == '#' {
+				q.all = true
+				i++
+			}
+			if i < len(path) && path[i] != '.' {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+			comps = append(comps, gjsonComp{query: q})
+			continue
+		}
+		var comp gjsonComp
+		var key strings.Builder
+		if path[i] == ':' {
+			comp.forceKey = true
+			i++
+		}
+		for ; i < len(path) && path[i] != '.'; i++ {
+			if path[i] == '\\' && i+1 < len(path) {
+				i++
+				comp.literal = true
+			}
+			key.WriteByte(path[i])
+		}
+		comp.key = key.String()
+		comp.count = comp.key == "#" && !comp.literal && !comp.forceKey
+		comps = append(comps, comp)
+	}
+	return comps, nil
+}
+
+//gjsonQueryEnd return the index of the ')' closing a query starting at start
+func gjsonQueryEnd(path string, start int) int {
+	depth := 1
+	for i := start; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			i++
+		case '"':
+			for i++; i < len(path) && path[i] != '"'; i++ {
+				if path[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+//parseGJSONQuery parse the inside of "#(...)"
+func parseGJSONQuery(cond string) (*gjsonQuery, error) {
+	q := &gjsonQuery{}
+	opStart := -1
+	for i := 0; i < len(cond) && opStart < 0; i++ {
+		switch cond[i] {
+		case '\\':
+			i++
+		case '=', '!', '<', '>', '%':
+			opStart = i
+		}
+	}
+	key := cond
+	if opStart >= 0 {
+		key = cond[:opStart]
+		rest := cond[opStart:]
+		for _, op := range []string{"==", "!=", "<=", ">=", "!%", "<", ">", "%", "="} {
+			if strings.HasPrefix(rest, op) {
+				q.op = op
+				rest = strings.TrimSpace(rest[len(op):])
+				break
+			}
+		}
+		if q.op == "" {
+			return nil, ErrorPathSyntax
+		}
+		if q.op == "=" {
+			q.op = "=="
+		}
+		if err := json.Unmarshal([]byte(rest), &q.value); err != nil {
+			return nil, err
+		}
+	}
+	key = strings.TrimSpace(key)
+	if key != "" {
+		comps, err := parseGJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		q.path = comps
+	}
+	return q, nil
+}
@@ -0,0 +1,293 @@
+package jsongo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+//ErrorBinaryFormat error if UnmarshalBinary is given data not produced by MarshalBinary
+var ErrorBinaryFormat = errors.New("jsongo: UnmarshalBinary: invalid data")
+
+//binaryVersion is the first byte of the MarshalBinary format, the version 1 has no tags
+const binaryVersion = 2
+
+const (
+	binaryDontExpand = 1 << iota
+	binaryFrozen
+	binaryLenient
+	binaryGenerateOnly
+	binaryRequired
+	binaryCopyValues
+	binaryNumericKeys
+	binaryKeepEscapes
+)
+
+//MarshalBinary Make JSONNode a encoding.BinaryMarshaler, so it can be used with encoding/gob
+//
+//The types, UnmarshalDontExpand, UnmarshalLenient, Required, CopyValues, NumericKeys, KeepEscapes, Freeze, FloatPrecision, GenerateOnly, GenerateExcept, Enum and Tag are kept.
+//Values are stored as json so they come back like after an Unmarshal (numbers as float64...).
+//Constraints, intern pools and computed functions are not kept, computed values are stored evaluated
+func (that *JSONNode) MarshalBinary() ([]byte, error) {
+	e := &encodeState{root: that}
+	return e.appendBinary([]byte{binaryVersion}, that)
+}
+
+func (e *encodeState) appendBinary(dst []byte, node *JSONNode) ([]byte, error) {
+	dst = append(dst, byte(node.t))
+	var flags byte
+	if node.dontExpand {
+		flags |= binaryDontExpand
+	}
+	if node.frozen {
+		flags |= binaryFrozen
+	}
+	o := node.opts()
+	if o.lenient {
+		flags |= binaryLenient
+	}
+	if o.filter != nil && o.filter.only {
+		flags |= binaryGenerateOnly
+	}
+	if o.required {
+		flags |= binaryRequired
+	}
+	if o.copyValues {
+		flags |= binaryCopyValues
+	}
+	if o.numeric {
+		flags |= binaryNumericKeys
+	}
+	if o.keepEscapes {
+		flags |= binaryKeepEscapes
+	}
+	dst = append(dst, flags)
+	dst = binary.AppendUvarint(dst, uint64(o.precision))
+	var patterns []string
+	if o.filter != nil {
+		for _, q := range o.filter.patterns {
+			patterns = append(patterns, q.String())
+		}
+	}
+	dst = appendBinaryStrings(dst, patterns)
+	enum := make([]string, len(o.enum))
+	for i := range o.enum {
+		data, err := GetCodec().Marshal(o.enum[i])
+		if err != nil {
+			return nil, err
+		}
+		enum[i] = string(data)
+	}
+	dst = appendBinaryStrings(dst, enum)
+	dst = appendBinaryStrings(dst, o.tags)
+	var err error
+	switch node.t {
+	case TypeMap:
+		dst = binary.AppendUvarint(dst, uint64(len(node.m)))
+		for _, key := range sortedKeys(node.m) {
+			dst = appendBinaryString(dst, key)
+			if dst, err = e.appendBinary(dst, node.m[key]); err != nil {
+				return nil, err
+			}
+		}
+	case TypeArray:
+		dst = binary.AppendUvarint(dst, uint64(len(node.a)))
+		for i := range node.a {
+			if dst, err = e.appendBinary(dst, node.a[i]); err != nil {
+				return nil, err
+			}
+		}
+	case TypeValue:
+		v := node.v
+		if node.compute != nil {
+			v = node.compute(e.root)
+		}
+		start := len(e.buf)
+		if err := e.encodeValue(v); err != nil {
+			return nil, err
+		}
+		dst = appendBinaryString(dst, string(e.buf[start:]))
+		e.buf = e.buf[:start]
+	}
+	return dst, nil
+}
+
+func appendBinaryString(dst []byte, s string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+func appendBinaryStrings(dst []byte, list []string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(list)))
+	for _, s := range list {
+		dst = appendBinaryString(dst, s)
+	}
+	return dst
+}
+
+//UnmarshalBinary Make JSONNode a encoding.BinaryUnmarshaler, it replaces that JSONNode by the one encoded by MarshalBinary
+func (that *JSONNode) UnmarshalBinary(data []byte) error {
+	if that.frozen {
+		return ErrorFrozen
+	}
+	if len(data) == 0 || data[0] == 0 || data[0] > binaryVersion {
+		return fmt.Errorf("%w: unknown version", ErrorBinaryFormat)
+	}
+	r := &binaryReader{data: data[1:], version: data[0]}
+	node := &JSONNode{}
+	if err := r.node(node); err != nil {
+		return err
+	}
+	if len(r.data) != 0 {
+		return fmt.Errorf("%w: trailing data", ErrorBinaryFormat)
+	}
+	that.replace(*node)
+	return nil
+}
+
+//binaryReader read the MarshalBinary format
+type binaryReader struct {
+	data    []byte
+	version byte
+}
+
+func (r *binaryReader) node(node *JSONNode) error {
+	if len(r.data) < 2 || JSONNodeType(r.data[0]) >= typeError {
+		return ErrorBinaryFormat
+	}
+	t, flags := JSONNodeType(r.data[0]), r.data[1]
+	r.data = r.data[2:]
+	precision, err := r.uvarint()
+	if err != nil {
+		return err
+	}
+	patterns, err := r.strings()
+	if err != nil {
+		return err
+	}
+	enum, err := r.strings()
+	if err != nil {
+		return err
+	}
+	var tags []string
+	if r.version >= 2 {
+		if tags, err = r.strings(); err != nil {
+			return err
+		}
+	}
+	switch t {
+	case TypeMap:
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		node.SetType(TypeMap)
+		for i := uint64(0); i < n; i++ {
+			key, err := r.string()
+			if err != nil {
+				return err
+			}
+			if err := r.node(node.Map(key)); err != nil {
+				return err
+			}
+		}
+	case TypeArray:
+		n, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		if n > uint64(len(r.data)) {
+			return ErrorBinaryFormat
+		}
+		node.Resize(int(n))
+		for i := range node.a {
+			if err := r.node(node.a[i]); err != nil {
+				return err
+			}
+		}
+	case TypeValue:
+		value, err := r.string()
+		if err != nil {
+			return err
+		}
+		if err := node.unmarshalValue([]byte(value), &decodeState{}); err != nil {
+			return fmt.Errorf("%w: %w", ErrorBinaryFormat, err)
+		}
+	case TypeNull:
+		node.SetNull()
+	}
+	o := *node.opts()
+	o.precision = int(precision)
+	o.lenient = flags&binaryLenient != 0
+	o.required = flags&binaryRequired != 0
+	o.copyValues = flags&binaryCopyValues != 0
+	o.numeric = flags&binaryNumericKeys != 0
+	o.keepEscapes = flags&binaryKeepEscapes != 0
+	node.dontExpand = flags&binaryDontExpand != 0
+	if len(patterns) > 0 {
+		filter := &generateFilter{only: flags&binaryGenerateOnly != 0, patterns: make([]*Query, len(patterns))}
+		for i := range patterns {
+			if filter.patterns[i], err = CompileQuery(patterns[i]); err != nil {
+				return fmt.Errorf("%w: %w", ErrorBinaryFormat, err)
+			}
+		}
+		o.filter = filter
+	}
+	for _, value := range enum {
+		var v interface{}
+		if err := GetCodec().Unmarshal([]byte(value), &v); err != nil {
+			return fmt.Errorf("%w: %w", ErrorBinaryFormat, err)
+		}
+		o.enum = append(slices.Clip(o.enum), v)
+	}
+	if len(tags) > 0 {
+		o.tags = tags
+	}
+	if node.options != nil || o.precision != 0 || o.lenient || o.required || o.copyValues || o.numeric || o.keepEscapes || o.filter != nil || len(o.enum) > 0 || len(o.tags) > 0 {
+		node.options = &o
+	}
+	node.frozen = flags&binaryFrozen != 0
+	return nil
+}
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		return 0, ErrorBinaryFormat
+	}
+	r.data = r.data[n:]
+	return v, nil
+}
+
+func (r *binaryReader) string() (string, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(len(r.data)) {
+		return "", ErrorBinaryFormat
+	}
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+	return s, nil
+}
+
+func (r *binaryReader) strings() ([]string, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.data)) {
+		return nil, ErrorBinaryFormat
+	}
+	var list []string
+	for i := uint64(0); i < n; i++ {
+		s, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+package jsongo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGobRoundTrip(t *testing.T) {
+	var root JSONNode
+	root.At("name").Tag("public", "id").Required(true).Val("a")
+	root.At("level").Enum("debug", "info").Val("info")
+	root.At("ratio").FloatPrecision(2).Val(0.5)
+	root.At("list").SetType(TypeArray)
+	root.At("list", Append).Val(1)
+	root.At("list", Append).SetNull()
+	root.At("raw").UnmarshalDontExpand(true, false)
+	root.Freeze()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&root); err != nil {
+		t.Fatal(err)
+	}
+	var got JSONNode
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, _ := root.MarshalCanonical(); !bytes.Equal(mustCanonical(t, &got), want) {
+		t.Fatalf("got %s, want %s", mustCanonical(t, &got), want)
+	}
+	name := got.At("name")
+	if !slices.Equal(name.Tags(), []string{"public", "id"}) || !name.IsRequired() {
+		t.Errorf("name has tags %v and required %v", name.Tags(), name.IsRequired())
+	}
+	if len(got.At("level").opts().enum) != 2 || got.At("ratio").opts().precision == 0 {
+		t.Error("the enum or the precision was lost")
+	}
+	if !got.At("raw").dontExpand || !got.IsFrozen() || got.At("list", 1).GetType() != TypeNull {
+		t.Error("the flags or the null were lost")
+	}
+}
+
+func TestUnmarshalBinaryVersion1(t *testing.T) {
+	//a TypeValue without flags, precision, patterns nor enum holding 1
+	var node JSONNode
+	if err := node.UnmarshalBinary([]byte{1, byte(TypeValue), 0, 0, 0, 0, 1, '1'}); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustCanonical(t, &node); string(got) != "1" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	var root JSONNode
+	root.At("a", 0).Tag("t").Val("x")
+	data, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(data); i++ {
+		var node JSONNode
+		if err := node.UnmarshalBinary(data[:i]); !errors.Is(err, ErrorBinaryFormat) {
+			t.Errorf("truncated to %d bytes: got %v", i, err)
+		}
+	}
+	var node JSONNode
+	if err := node.UnmarshalBinary(append(data, 0)); !errors.Is(err, ErrorBinaryFormat) {
+		t.Errorf("trailing data: got %v", err)
+	}
+	if err := node.UnmarshalBinary(append([]byte{binaryVersion + 1}, data[1:]...)); !errors.Is(err, ErrorBinaryFormat) {
+		t.Errorf("unknown version: got %v", err)
+	}
+}
+
+func mustCanonical(t *testing.T, node *JSONNode) []byte {
+	t.Helper()
+	data, err := node.MarshalCanonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,111 @@
+package jsongo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//MarshalCanonical Return the canonical JSON form of that JSONNode
+//
+//keys are sorted, there is no space and numbers are normalized (1.0, 1e0 and 1 are all written 1)
+//so structurally equal trees have the same canonical form
+func (that *JSONNode) MarshalCanonical() ([]byte, error) {
+	e := &encodeState{root: that, canonical: true}
+	if err := e.encode(that); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+//canonicalize rewrite the user value written in e.buf from start in its canonical form
+//
+//Only the values given to the Codec, and the floats, are decoded again: the tree itself is written canonical
+func (e *encodeState) canonicalize(start int) error {
+	data := e.buf[start:]
+	if len(data) == 0 || data[0] == 't' || data[0] == 'f' || data[0] == 'n' {
+		return nil
+	}
+	if data[0] == '-' || data[0] >= '0' && data[0] <= '9' {
+		e.buf = append(e.buf[:start], normalizeNumber(json.Number(data))...)
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	e.buf = appendCanonical(e.buf[:start], v)
+	return nil
+}
+
+//Hash Return the sha256 of the canonical form of that JSONNode (see MarshalCanonical)
+//
+//Hash panic if that JSONNode cant be marshaled
+func (that *JSONNode) Hash() [32]byte {
+	data, err := that.MarshalCanonical()
+	if err != nil {
+		panic(err)
+	}
+	return sha256.Sum256(data)
+}
+
+//appendCanonical append the canonical form of a value decoded with json.Decoder.UseNumber
+func appendCanonical(dst []byte, v interface{}) []byte {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(vv))
+		for key := range vv {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		dst = append(dst, '{')
+		for i, key := range keys {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendCanonical(dst, key)
+			dst = append(dst, ':')
+			dst = appendCanonical(dst, vv[key])
+		}
+		return append(dst, '}')
+	case []interface{}:
+		dst = append(dst, '[')
+		for i := range vv {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendCanonical(dst, vv[i])
+		}
+		return append(dst, ']')
+	case json.Number:
+		return append(dst, normalizeNumber(vv)...)
+	}
+	b, _ := json.Marshal(v)
+	return append(dst, b...)
+}
+
+//normalizeNumber return the shortest way to write a json number
+func normalizeNumber(n json.Number) string {
+	s := string(n)
+	if !strings.ContainsAny(s, ".eE") {
+		if i, ok := new(big.Int).SetString(s, 10); ok {
+			return i.String()
+		}
+		return s
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
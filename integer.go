@@ -0,0 +1,95 @@
+package jsongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+//ErrorIntOverflow error if GetInt64 or GetUint64 find a number out of the range of the requested type
+var ErrorIntOverflow = errors.New("jsongo: integer overflow")
+
+//ErrorIntFraction error if GetInt64 or GetUint64 find a number with a fractional part
+var ErrorIntFraction = errors.New("jsongo: number has a fractional part")
+
+//GetInt64 Return the value of that JSONNode as an int64
+//
+//Any number is accepted (int, uint, float, json.Number or a pointer to one) as long as it is an integer in the int64 range,
+//otherwise ErrorIntOverflow or ErrorIntFraction is returned instead of a truncated value.
+//Floats are only accepted below 2^53 in absolute value, above they may have been rounded and ErrorIntOverflow is returned
+func (that *JSONNode) GetInt64() (int64, error) {
+	r, err := that.rat()
+	if err != nil {
+		return 0, err
+	}
+	if !r.Num().IsInt64() {
+		return 0, ErrorIntOverflow
+	}
+	return r.Num().Int64(), nil
+}
+
+//GetUint64 Return the value of that JSONNode as an uint64, see GetInt64
+func (that *JSONNode) GetUint64() (uint64, error) {
+	r, err := that.rat()
+	if err != nil {
+		return 0, err
+	}
+	if !r.Num().IsUint64() {
+		return 0, ErrorIntOverflow
+	}
+	return r.Num().Uint64(), nil
+}
+
+//rat return the value of that JSONNode as an exact integer
+func (that *JSONNode) rat() (*big.Rat, error) {
+	if that.t != TypeValue {
+		return nil, ErrorRetrieveUserValue
+	}
+	v := indirect(that.Get())
+	r := new(big.Rat)
+	if num, ok := v.(json.Number); ok {
+		if _, ok := r.SetString(string(num)); !ok {
+			return nil, ErrorGetType
+		}
+	} else {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			r.SetInt64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			r.SetInt(new(big.Int).SetUint64(rv.Uint()))
+		case reflect.Float32, reflect.Float64:
+			f := rv.Float()
+			//from 2^53 a float64 cannot tell neighbouring integers apart, the value may already be rounded
+			if math.IsNaN(f) || math.Abs(f) >= 1<<53 {
+				return nil, ErrorIntOverflow
+			}
+			r.SetFloat64(f)
+		default:
+			return nil, ErrorGetType
+		}
+	}
+	if !r.IsInt() {
+		return nil, ErrorIntFraction
+	}
+	return r, nil
+}
+
+//preciseInt return the integer in data as an int64 or an uint64 if f, the float64 decoded from data, may have lost precision
+func preciseInt(data []byte, f float64) interface{} {
+	if math.Abs(f) < 1<<53 {
+		return f
+	}
+	s := string(bytes.TrimSpace(data))
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u
+	}
+	return f
+}
@@ -0,0 +1,52 @@
+package jsongo
+
+import (
+	"sync"
+)
+
+//InternPool hold one copy of each string it is given so identical strings can share their memory
+//
+//An InternPool is safe for concurrent use and can be shared by many trees, see UseInternPool
+type InternPool struct {
+	mu      sync.Mutex
+	strings map[string]string
+}
+
+//NewInternPool Return a new empty InternPool
+func NewInternPool() *InternPool {
+	return &InternPool{strings: make(map[string]string)}
+}
+
+//Intern Return the copy of s held by the pool, adding s to the pool if it is not there yet
+func (p *InternPool) Intern(s string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if interned, ok := p.strings[s]; ok {
+		return interned
+	}
+	p.strings[s] = s
+	return s
+}
+
+//Len Return the number of strings in the pool
+func (p *InternPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.strings)
+}
+
+//Reset remove every string from the pool
+func (p *InternPool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.strings = make(map[string]string)
+}
+
+//UseInternPool make Unmarshal intern in pool the keys of the maps it generates under that JSONNode
+//
+//Large arrays of similar objects then share one string per key instead of one per object.
+//Use NewInternPool for a single tree or share the same pool between trees. A nil pool disable interning
+func (that *JSONNode) UseInternPool(pool *InternPool) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.intern = pool })
+	return that
+}
@@ -0,0 +1,110 @@
+package jsongo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//ErrorInterpolateMissing error if Interpolate find an unknown variable and InterpolateOptions.Missing is MissingError
+var ErrorInterpolateMissing = errors.New("jsongo: Interpolate: unknown variable")
+
+//MissingPolicy decide what Interpolate does with a reference to an unknown variable
+type MissingPolicy int
+
+const (
+	//MissingKeep leave the reference as is
+	MissingKeep MissingPolicy = iota
+	//MissingEmpty replace the reference by an empty string
+	MissingEmpty
+	//MissingError stop and return ErrorInterpolateMissing
+	MissingError
+)
+
+//InterpolateOptions are the options of Interpolate
+type InterpolateOptions struct {
+	Missing MissingPolicy
+	Env     bool //look for unknown variables in the environment
+}
+
+//Interpolate expand every ${VAR} and {{placeholder}} reference in the string values of that JSONNode and its children
+//
+//vars are looked up first, then the environment if opts.Env is set
+func (that *JSONNode) Interpolate(vars map[string]string, opts InterpolateOptions) error {
+	switch that.t {
+	case TypeMap:
+		for _, key := range sortedKeys(that.m) {
+			if err := that.m[key].Interpolate(vars, opts); err != nil {
+				return err
+			}
+		}
+	case TypeArray:
+		for i := range that.a {
+			if err := that.a[i].Interpolate(vars, opts); err != nil {
+				return err
+			}
+		}
+	case TypeValue:
+		s, ok := that.Get().(string)
+		if !ok {
+			return nil
+		}
+		expanded, err := interpolateString(s, vars, opts)
+		if err != nil {
+			return err
+		}
+		if expanded != s {
+			if that.frozen {
+				return ErrorFrozen
+			}
+			that.Val(expanded)
+		}
+	}
+	return nil
+}
+
+func interpolateString(s string, vars map[string]string, opts InterpolateOptions) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexAny(s, "${")
+		if start < 0 || start == len(s)-1 {
+			break
+		}
+		var open, closing string
+		switch {
+		case strings.HasPrefix(s[start:], "${"):
+			open, closing = "${", "}"
+		case strings.HasPrefix(s[start:], "{{"):
+			open, closing = "{{", "}}"
+		default:
+			b.WriteString(s[:start+1])
+			s = s[start+1:]
+			continue
+		}
+		end := strings.Index(s[start+len(open):], closing)
+		if end < 0 {
+			break
+		}
+		ref := s[start : start+len(open)+end+len(closing)]
+		name := strings.TrimSpace(ref[len(open) : len(ref)-len(closing)])
+		b.WriteString(s[:start])
+		s = s[start+len(ref):]
+		val, ok := vars[name]
+		if !ok && opts.Env {
+			val, ok = os.LookupEnv(name)
+		}
+		if ok {
+			b.WriteString(val)
+			continue
+		}
+		switch opts.Missing {
+		case MissingKeep:
+			b.WriteString(ref)
+		case MissingError:
+			return "", fmt.Errorf("%w %q", ErrorInterpolateMissing, name)
+		}
+	}
+	b.WriteString(s)
+	return b.String(), nil
+}
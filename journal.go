@@ -0,0 +1,168 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+//Journal record the changes made to the values of a tree, see AttachJournal
+//
+//A Journal is safe for concurrent use, the tree it is attached to is not
+type Journal struct {
+	mu      sync.Mutex
+	entries []JournalEntry
+	author  string
+}
+
+//JournalEntry is a change recorded by a Journal
+type JournalEntry struct {
+	Path   Path            //path of the JSONNode from the root the Journal is attached to
+	Old    json.RawMessage //json of the JSONNode before the change, nil if it was TypeUndefined
+	New    json.RawMessage //json of the JSONNode after the change, nil if it was removed or unset
+	Time   time.Time
+	Author string //author set with SetAuthor when the change was made
+}
+
+//journalEntryJSON is the json form of a JournalEntry
+type journalEntryJSON struct {
+	Path   string          `json:"path"`
+	Old    json.RawMessage `json:"old,omitempty"`
+	New    json.RawMessage `json:"new,omitempty"`
+	Time   time.Time       `json:"time"`
+	Author string          `json:"author,omitempty"`
+}
+
+//NewJournal Return an empty Journal
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+//AttachJournal record in j every change made to the values of that JSONNode and its children, nil stops the recording
+//
+//Val, Unmarshal, SetNull, DelKey, Unset, Copy, Resize, Truncate and ToArray are recorded with the path of the changed
+//JSONNode from that JSONNode, the children added later are recorded too.
+//Attaching a Journal replaces the one attached to that JSONNode, not the ones attached to its parents or children.
+//Children shared with another tree by a shallow Copy record their changes in the Journal of the last tree they were copied in
+func (that *JSONNode) AttachJournal(j *Journal) *JSONNode {
+	t, prefix := that.tracker()
+	t.mu.Lock()
+	t.journals = slices.DeleteFunc(t.journals, func(attached attachedJournal) bool { return slices.Equal(attached.prefix, prefix) })
+	if j != nil {
+		t.journals = append(t.journals, attachedJournal{journal: j, prefix: prefix})
+	}
+	t.mu.Unlock()
+	that.untrack(t)
+	return that
+}
+
+func (j *Journal) add(entry JournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entry.Author = j.author
+	j.entries = append(j.entries, entry)
+}
+
+//SetAuthor set the author of the following changes
+func (j *Journal) SetAuthor(author string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.author = author
+}
+
+//Entries Return a copy of the recorded changes, oldest first
+func (j *Journal) Entries() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return slices.Clone(j.entries)
+}
+
+//Len Return the number of recorded changes
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.entries)
+}
+
+//MarshalJSON export the Journal as a json array of {"path", "old", "new", "time", "author"} objects
+//
+//old is absent if the JSONNode was TypeUndefined and new if it was removed
+func (j *Journal) MarshalJSON() ([]byte, error) {
+	entries := j.Entries()
+	out := make([]journalEntryJSON, len(entries))
+	for i, entry := range entries {
+		out[i] = journalEntryJSON{Path: entry.Path.String(), Old: entry.Old, New: entry.New, Time: entry.Time, Author: entry.Author}
+	}
+	return json.Marshal(out)
+}
+
+//UnmarshalJSON load a Journal exported by MarshalJSON, to Replay it
+func (j *Journal) UnmarshalJSON(data []byte) error {
+	var in []journalEntryJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	entries := make([]JournalEntry, len(in))
+	for i, entry := range in {
+		path, err := ParsePath(entry.Path)
+		if err != nil {
+			return err
+		}
+		entries[i] = JournalEntry{Path: path, Old: entry.Old, New: entry.New, Time: entry.Time, Author: entry.Author}
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = entries
+	return nil
+}
+
+//Replay apply the recorded changes to root, oldest first
+//
+//Each change set the JSONNode at its path to its new json, building the path if needed, or remove it.
+//Replay stop at the first error
+func (j *Journal) Replay(root *JSONNode) error {
+	for _, entry := range j.Entries() {
+		if err := entry.apply(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (entry *JournalEntry) apply(root *JSONNode) error {
+	if entry.New == nil {
+		parent, ok := root.find(entry.Path.elems[:max(len(entry.Path.elems)-1, 0)])
+		if !ok {
+			return nil
+		}
+		if len(entry.Path.elems) == 0 {
+			parent.Unset()
+			return nil
+		}
+		last := entry.Path.elems[len(entry.Path.elems)-1]
+		if parent.t == TypeMap && !last.isIndex {
+			parent.DelKey(last.key)
+		} else if child, ok := parent.find(entry.Path.elems[len(entry.Path.elems)-1:]); ok {
+			child.Unset()
+		}
+		return nil
+	}
+	tmp := &JSONNode{}
+	switch entry.New[0] {
+	case '{':
+		tmp.SetType(TypeMap)
+	case '[':
+		tmp.SetType(TypeArray)
+	}
+	if err := tmp.UnmarshalJSON(entry.New); err != nil {
+		return err
+	}
+	node, err := entry.Path.At(root)
+	if err != nil {
+		return err
+	}
+	node.Unset()
+	node.Copy(tmp, false)
+	return nil
+}
@@ -11,10 +11,10 @@
 package jsongo
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"reflect"
-	//"fmt"
 )
 
 //ErrorKeyAlreadyExist error if a key already exist in current JSONNode
@@ -26,9 +26,12 @@ var ErrorMultipleType = errors.New("jsongo this node is already set to a differe
 //ErrorArrayNegativeValue error if you ask for a negative index in an array
 var ErrorArrayNegativeValue = errors.New("jsongo negative index for array")
 
-//ErrorArrayNegativeValue error if you ask for a negative index in an array
+//ErrorAtUnsupportedType error if you use something else than an integer, a string or a fmt.Stringer as At argument
 var ErrorAtUnsupportedType = errors.New("jsongo Unsupported Type as At argument")
 
+//ErrorAtIndexOverflow error if you use an integer that does not fit in an int as At argument
+var ErrorAtIndexOverflow = errors.New("jsongo index overflow in At argument")
+
 //ErrorRetrieveUserValue error if you ask the value of a node that is not a value node
 var ErrorRetrieveUserValue = errors.New("jsongo Cannot retrieve node's value which is not of type value")
 
@@ -50,14 +53,58 @@ var ErrorDeleteKey = errors.New("jsongo: DelKey: This JSONNode is not a TypeMap"
 //ErrorCopyType error if you try to call Copy on a JSONNode that isnt a TypeUndefined
 var ErrorCopyType = errors.New("jsongo: Copy: This JSONNode is not a TypeUndefined")
 
+//ErrorFrozen error if you try to modify a JSONNode after Freeze was called on it
+var ErrorFrozen = errors.New("jsongo: this JSONNode is frozen and cannot be modified")
+
 //JSONNode Datastructure to build and maintain Nodes
 type JSONNode struct {
 	m          map[string]*JSONNode
-	a          []JSONNode
+	a          []*JSONNode
 	v          interface{}
-	vChanged   bool         //True if we changed the type of the value
-	t          JSONNodeType //Type of that JSONNode 0: Not defined, 1: map, 2: array, 3: value
-	dontExpand bool         //dont expand while Unmarshal
+	compute    func(root *JSONNode) interface{} //Computed value evaluated at marshal time
+	t          JSONNodeType                     //Type of that JSONNode 0: Not defined, 1: map, 2: array, 3: value, 4: null
+	options    *nodeOptions                     //settings few JSONNode use, nil until one is set
+	link       *changeLink                      //where the changes are sent, see AttachJournal and Subscribe
+	vChanged   bool                             //True if we changed the type of the value
+	dontExpand bool                             //dont expand while Unmarshal
+	unfilled   bool                             //TypeValue typed by BuildFromSpec, still waiting for its value, see CheckRequired
+	frozen     bool                             //read-only, see Freeze
+}
+
+//nodeOptions hold the settings of a JSONNode that are rarely used, so they dont make every JSONNode bigger
+//
+//nodeOptions are shared by the copies of a JSONNode and never modified once set, see setOptions
+type nodeOptions struct {
+	filter      *generateFilter //paths Unmarshal can generate, see GenerateOnly
+	intern      *InternPool     //pool for the keys created by Unmarshal, see UseInternPool
+	constrain   []Constraint    //checked by Val and Unmarshal, see Constrain
+	enum        []interface{}   //allowed values, see Enum
+	tags        []string        //never marshaled, see Tag
+	src         []byte          //input text of a string value written with escapes, see PreserveEscapes
+	precision   int             //decimal places of a float value plus one, 0 if not set, see FloatPrecision
+	lenient     bool            //coerce quoted numbers and booleans while Unmarshal, see UnmarshalLenient
+	required    bool            //must be in the input of Unmarshal, see Required
+	copyValues  bool            //Val deep copy its value, see CopyValues
+	numeric     bool            //At accept an int for a map and a numeric string for an array, see NumericKeys
+	keepEscapes bool            //Unmarshal keep the input text of the strings, see KeepEscapes
+}
+
+//noOptions is what opts return for a JSONNode without options
+var noOptions nodeOptions
+
+//opts return the options of that JSONNode, they must not be modified
+func (that *JSONNode) opts() *nodeOptions {
+	if that.options == nil {
+		return &noOptions
+	}
+	return that.options
+}
+
+//setOptions replace the options of that JSONNode by a copy modified by set
+func (that *JSONNode) setOptions(set func(o *nodeOptions)) {
+	o := *that.opts()
+	set(&o)
+	that.options = &o
 }
 
 //JSONNodeType is used to set, check and get the inner type of a JSONNode
@@ -72,64 +119,211 @@ const (
 	TypeArray
 	//TypeValue is set when a JSONNode is a Value Node
 	TypeValue
+	//TypeNull is set when a JSONNode is an explicit json null
+	TypeNull
 	//typeError help us detect errors
 	typeError
 )
 
+//Append can be used as an index with At to add a new element at the end of a TypeArray
+const Append = -1
+
 //At helps you move through your node by building them on the fly
 //
-//val can be string or int only
+//val can be strings, fmt.Stringers or any integer type
+//
+//strings and fmt.Stringers are keys for TypeMap
+//
+//integers are index in TypeArray (it will make array grow on the fly, so you should start to populate with the biggest index first)*
+//
+//Append (-1) is the index right after the last element of a TypeArray
 //
-//strings are keys for TypeMap
+//Unsupported types are detected before anything is built
 //
-//ints are index in TypeArray (it will make array grow on the fly, so you should start to populate with the biggest index first)*
+//The *JSONNode returned for an element stays valid when the TypeArray grows, until the element is removed
 func (that *JSONNode) At(val ...interface{}) *JSONNode {
+	keys, err := normalizeKeys(val)
+	if err != nil {
+		panic(err)
+	}
+	return that.at(keys)
+}
+
+//TryAt does the same as At but return an error instead of panicking
+//
+//nothing is built if an error is returned
+func (that *JSONNode) TryAt(val ...interface{}) (*JSONNode, error) {
+	keys, err := normalizeKeys(val)
+	if err != nil {
+		return nil, err
+	}
+	if err := that.checkAt(keys); err != nil {
+		return nil, err
+	}
+	return that.at(keys), nil
+}
+
+//at is At with keys already normalized to string or int
+func (that *JSONNode) at(val []interface{}) *JSONNode {
 	if len(val) == 0 {
 		return that
 	}
-	switch vv := val[0].(type) {
+	switch vv := that.numericKey(val[0]).(type) {
 	case string:
-		return that.atMap(vv, val[1:]...)
+		return that.atMap(vv, val[1:])
 	case int:
-		return that.atArray(vv, val[1:]...)
+		return that.atArray(vv, val[1:])
 	}
 	panic(ErrorAtUnsupportedType)
 }
 
+//checkAt return the error that at would panic with, without building anything
+func (that *JSONNode) checkAt(val []interface{}) error {
+	cur := that
+	for _, key := range val {
+		if cur != nil {
+			key = cur.numericKey(key)
+		}
+		switch kk := key.(type) {
+		case string:
+			if cur == nil {
+				continue
+			}
+			if cur.t != TypeUndefined && cur.t != TypeMap {
+				return ErrorMultipleType
+			}
+			if _, ok := cur.m[kk]; !ok && cur.frozen {
+				return ErrorFrozen
+			}
+			cur = cur.m[kk]
+		case int:
+			if kk < 0 && kk != Append {
+				return ErrorArrayNegativeValue
+			}
+			if cur == nil {
+				continue
+			}
+			if cur.t != TypeUndefined && cur.t != TypeArray {
+				return ErrorMultipleType
+			}
+			if cur.frozen && (cur.t == TypeUndefined || kk < 0 || kk >= len(cur.a)) {
+				return ErrorFrozen
+			}
+			if kk >= 0 && kk < len(cur.a) {
+				cur = cur.a[kk]
+			} else {
+				cur = nil
+			}
+		}
+	}
+	return nil
+}
+
+//normalizeKeys turn every At argument into a string or an int
+//
+//val is returned as is when there is nothing to convert
+func normalizeKeys(val []interface{}) ([]interface{}, error) {
+	for i := range val {
+		switch val[i].(type) {
+		case string, int:
+			continue
+		}
+		keys := make([]interface{}, len(val))
+		copy(keys, val[:i])
+		for ; i < len(val); i++ {
+			key, err := normalizeKey(val[i])
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = key
+		}
+		return keys, nil
+	}
+	return val, nil
+}
+
+//normalizeKey turn an At argument into a string or an int
+func normalizeKey(val interface{}) (interface{}, error) {
+	switch vv := val.(type) {
+	case string:
+		return vv, nil
+	case int:
+		return vv, nil
+	case int8:
+		return int(vv), nil
+	case int16:
+		return int(vv), nil
+	case int32:
+		return int(vv), nil
+	case int64:
+		if vv > math.MaxInt || vv < math.MinInt {
+			return nil, ErrorAtIndexOverflow
+		}
+		return int(vv), nil
+	case uint:
+		return uintKey(uint64(vv))
+	case uint8:
+		return int(vv), nil
+	case uint16:
+		return int(vv), nil
+	case uint32:
+		return uintKey(uint64(vv))
+	case uint64:
+		return uintKey(vv)
+	case uintptr:
+		return uintKey(uint64(vv))
+	case fmt.Stringer:
+		return vv.String(), nil
+	}
+	return nil, ErrorAtUnsupportedType
+}
+
+func uintKey(val uint64) (interface{}, error) {
+	if val > math.MaxInt {
+		return nil, ErrorAtIndexOverflow
+	}
+	return int(val), nil
+}
+
 //atMap return the JSONNode in current map
-func (that *JSONNode) atMap(key string, val ...interface{}) *JSONNode {
+func (that *JSONNode) atMap(key string, val []interface{}) *JSONNode {
 	if that.t != TypeUndefined && that.t != TypeMap {
 		panic(ErrorMultipleType)
 	}
+	if next, ok := that.m[key]; ok {
+		return next.at(val)
+	}
+	that.mutate()
 	if that.m == nil {
 		that.m = make(map[string]*JSONNode)
 		that.t = TypeMap
 	}
-	if next, ok := that.m[key]; ok {
-		return next.At(val...)
-	}
 	that.m[key] = new(JSONNode)
-	return that.m[key].At(val...)
+	that.linkKey(key)
+	return that.m[key].at(val)
 }
 
 //atArray return the JSONNode in current TypeArray (and make it grow if necessary)
-func (that *JSONNode) atArray(key int, val ...interface{}) *JSONNode {
-	if that.t == TypeUndefined {
-		that.t = TypeArray
-	} else if that.t != TypeArray {
+func (that *JSONNode) atArray(key int, val []interface{}) *JSONNode {
+	if that.t != TypeUndefined && that.t != TypeArray {
 		panic(ErrorMultipleType)
 	}
-	if key < 0 {
+	if key == Append {
+		key = len(that.a)
+	} else if key < 0 {
 		panic(ErrorArrayNegativeValue)
 	}
+	if that.t == TypeUndefined || key >= len(that.a) {
+		that.mutate()
+		that.t = TypeArray
+	}
 	if key >= len(that.a) {
-		newa := make([]JSONNode, key+1)
-		for i := 0; i < len(that.a); i++ {
-			newa[i] = that.a[i]
-		}
-		that.a = newa
+		from := len(that.a)
+		//append keeps spare capacity, so appending elements one by one does not copy the whole array each time
+		that.a = appendElems(that.a, key+1-len(that.a))
+		that.linkElems(from)
 	}
-	return that.a[key].At(val...)
+	return that.a[key].at(val)
 }
 
 //Map Turn this JSONNode to a TypeMap and/or Create a new element for key if necessary and return it
@@ -137,48 +331,52 @@ func (that *JSONNode) Map(key string) *JSONNode {
 	if that.t != TypeUndefined && that.t != TypeMap {
 		panic(ErrorMultipleType)
 	}
+	if _, ok := that.m[key]; ok {
+		return that.m[key]
+	}
+	that.mutate()
 	if that.m == nil {
 		that.m = make(map[string]*JSONNode)
 		that.t = TypeMap
 	}
-	if _, ok := that.m[key]; ok {
-		return that.m[key]
-	}
 	that.m[key] = &JSONNode{}
+	that.linkKey(key)
 	return that.m[key]
 }
 
 //Array Turn this JSONNode to a TypeArray and/or set the array size (reducing size will make you loose data)
+//
+//The elements are moved to the returned slice, which the JSONNode use until its size change:
+//the *JSONNode returned by At before for them are not updated anymore, and changing the length of the slice does nothing.
+//
+//Deprecated: the returned slice lets you break the invariants of the JSONNode, use Resize, Truncate, Reserve and At instead
 func (that *JSONNode) Array(size int) *[]JSONNode {
-	if that.t == TypeUndefined {
-		that.t = TypeArray
-	} else if that.t != TypeArray {
-		panic(ErrorMultipleType)
-	}
-	if size < 0 {
-		panic(ErrorArrayNegativeValue)
-	}
-	var min int
-	if size < len(that.a) {
-		min = size
-	} else {
-		min = len(that.a)
+	if err := that.Resize(size); err != nil {
+		panic(err)
 	}
-	newa := make([]JSONNode, size)
-	for i := 0; i < min; i++ {
-		newa[i] = that.a[i]
+	elems := make([]JSONNode, len(that.a))
+	for i := range that.a {
+		elems[i] = *that.a[i]
+		that.a[i] = &elems[i]
 	}
-	that.a = newa
-	return &(that.a)
+	return &elems
 }
 
 //Val Turn this JSONNode to Value type and/or set that value to val
+//
+//Val panic with ErrorConstraint if val is rejected by a Constraint of that JSONNode
+//
+//val is deep copied if CopyValues was set on that JSONNode, see ValNoCopy
 func (that *JSONNode) Val(val interface{}) {
-	if that.t == TypeUndefined {
-		that.t = TypeValue
-	} else if that.t != TypeValue {
-		panic(ErrorMultipleType)
+	if that.opts().copyValues {
+		val = deepCopy(val)
 	}
+	that.ValNoCopy(val)
+}
+
+//setVal is Val without the checks
+func (that *JSONNode) setVal(val interface{}) {
+	that.t = TypeValue
 	rt := reflect.TypeOf(val)
 	var finalval interface{}
 	if val == nil {
@@ -199,6 +397,48 @@ func (that *JSONNode) Val(val interface{}) {
 		finalval = val
 	}
 	that.v = finalval
+	that.compute = nil
+	that.unfilled = false
+	that.dropSource()
+}
+
+//Compute Turn this JSONNode to Value type and set a function that will compute its value when marshaling
+//
+//root is the JSONNode on which the marshaling started. Get will return nil for such a node, Val will remove the function
+func (that *JSONNode) Compute(fn func(root *JSONNode) interface{}) {
+	that.mutate()
+	if that.t != TypeUndefined && that.t != TypeValue {
+		panic(ErrorMultipleType)
+	}
+	defer that.logChange(that.snapshot())
+	//computed values are not checked, so neither is the nil they start from
+	that.setVal(nil)
+	that.v = nil
+	that.vChanged = false
+	that.compute = fn
+}
+
+//SetNull Turn this JSONNode to TypeNull
+func (that *JSONNode) SetNull() {
+	if that.t != TypeUndefined && that.t != TypeNull {
+		panic(ErrorMultipleType)
+	}
+	that.mutate()
+	defer that.logChange(that.snapshot())
+	that.t = TypeNull
+}
+
+//IsNull Return true if the JSONNode is TypeNull or a TypeValue holding nil
+//
+//TypeUndefined is not null, this is how you know if a field was absent or present and null after Unmarshal
+func (that *JSONNode) IsNull() bool {
+	switch that.t {
+	case TypeNull:
+		return true
+	case TypeValue:
+		return that.compute == nil && that.Get() == nil
+	}
+	return false
 }
 
 //Get Return value of a TypeValue as interface{}
@@ -239,7 +479,7 @@ func (that *JSONNode) GetKeys() []interface{} {
 
 //Len Return the length of the current Node
 //
-// if TypeUndefined return 0
+// if TypeUndefined or TypeNull return 0
 //
 // if TypeValue return 1
 //
@@ -267,14 +507,16 @@ func (that *JSONNode) SetType(t JSONNodeType) *JSONNode {
 	if t >= typeError {
 		panic(ErrorUnknowType)
 	}
+	that.mutate()
 	that.t = t
 	switch t {
 	case TypeMap:
 		that.m = make(map[string]*JSONNode, 0)
 	case TypeArray:
-		that.a = make([]JSONNode, 0)
+		that.a = make([]*JSONNode, 0)
 	case TypeValue:
-		that.Val(nil)
+		//the constraints and the enum restrict the values set by the user, not this initialization
+		that.setVal(nil)
 	}
 	return that
 }
@@ -288,19 +530,28 @@ func (that *JSONNode) GetType() JSONNodeType {
 //
 //if deepCopy is true we will copy all the children recursively else we will share the children
 //
+//the options of the node (tags, constraints, FloatPrecision, Required...) are copied too
+//
 //return the current JSONNode
 func (that *JSONNode) Copy(other *JSONNode, deepCopy bool) *JSONNode {
 	if that.t != TypeUndefined {
 		panic(ErrorCopyType)
 	}
-	
-	if other.t == TypeValue {
+	that.mutate()
+	//the children built by a deep copy are linked to the tracker of that JSONNode once, at the end
+	link := that.link
+	that.link = nil
+	if other.t == TypeValue || other.t == TypeNull {
 		*that = *other
+		that.frozen = false
 	} else if other.t == TypeArray {
 		if !deepCopy {
 			*that = *other
+			that.frozen = false
+			//growing one of the arrays must not write in the storage of the other
+			that.a = that.a[:len(that.a):len(that.a)]
 		} else {
-			that.Array(len(other.a))
+			that.Resize(len(other.a))
 			for i := range other.a {
 				that.At(i).Copy(other.At(i), deepCopy)
 			}
@@ -317,13 +568,23 @@ func (that *JSONNode) Copy(other *JSONNode, deepCopy bool) *JSONNode {
 			}
 		}
 	}
+	that.options = other.options
+	that.link = link
+	if link != nil {
+		that.linkTo(link.tracker, link.path)
+		that.logChange(nil)
+	}
+	that.stamp()
 	return that
 }
 
 
 //Unset Will unset everything in the JSONnode. All the children data will be lost
 func (that *JSONNode) Unset() {
-	*that = JSONNode{}
+	that.mutate()
+	defer that.logChange(that.snapshot())
+	*that = JSONNode{link: that.link}
+	that.stamp()
 }
 
 //DelKey will remove a key in the map.
@@ -333,10 +594,48 @@ func (that *JSONNode) DelKey(key string) *JSONNode {
 	if that.t != TypeMap {
 		panic(ErrorDeleteKey)
 	}
+	that.mutate()
+	if child, ok := that.m[key]; ok && child.link != nil {
+		child.logRemove()
+	}
 	delete(that.m, key)
 	return that
 }
 
+//Freeze make that JSONNode and all its children read-only
+//
+//Any call that would modify them (Val, Map, At building a node, Array, SetType, Copy, Unset, DelKey, Unmarshal...) will fail with ErrorFrozen.
+//
+//Values stored with Val are not copied, whoever holds them can still modify them, unless CopyValues was set.
+//Use Copy with deepCopy on a new JSONNode to get a modifiable version
+func (that *JSONNode) Freeze() *JSONNode {
+	that.frozen = true
+	switch that.t {
+	case TypeMap:
+		for k := range that.m {
+			that.m[k].Freeze()
+		}
+	case TypeArray:
+		for k := range that.a {
+			that.a[k].Freeze()
+		}
+	}
+	return that
+}
+
+//IsFrozen Return true if Freeze was called on that JSONNode
+func (that *JSONNode) IsFrozen() bool {
+	return that.frozen
+}
+
+//mutate must be called before modifying a JSONNode, it panics with ErrorFrozen if the node is read-only
+func (that *JSONNode) mutate() {
+	if that.frozen {
+		panic(ErrorFrozen)
+	}
+	that.stamp()
+}
+
 //UnmarshalDontExpand set or not if Unmarshall will generate anything in that JSONNode and its children
 //
 //val: will change the expanding rules for this node
@@ -368,105 +667,3 @@ func (that *JSONNode) UnmarshalDontExpand(val bool, recurse bool) *JSONNode {
 	}
 	return that
 }
-
-//MarshalJSON Make JSONNode a Marshaler Interface compatible
-func (that *JSONNode) MarshalJSON() ([]byte, error) {
-	var ret []byte
-	var err error
-	switch that.t {
-	case TypeMap:
-		ret, err = json.Marshal(that.m)
-	case TypeArray:
-		ret, err = json.Marshal(that.a)
-	case TypeValue:
-		ret, err = json.Marshal(that.v)
-	default:
-		ret, err = json.Marshal(nil)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return ret, err
-}
-
-func (that *JSONNode) unmarshalMap(data []byte) error {
-	tmp := make(map[string]json.RawMessage)
-	err := json.Unmarshal(data, &tmp)
-	if err != nil {
-		return err
-	}
-	for k := range tmp {
-		if _, ok := that.m[k]; ok {
-			err := json.Unmarshal(tmp[k], that.m[k])
-			if err != nil {
-				return err
-			}
-		} else if !that.dontExpand {
-			err := json.Unmarshal(tmp[k], that.Map(k))
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
-
-func (that *JSONNode) unmarshalArray(data []byte) error {
-	var tmp []json.RawMessage
-	err := json.Unmarshal(data, &tmp)
-	if err != nil {
-		return err
-	}
-	for i := len(tmp) - 1; i >= 0; i-- {
-		if !that.dontExpand || i < len(that.a) {
-			err := json.Unmarshal(tmp[i], that.At(i))
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
-
-func (that *JSONNode) unmarshalValue(data []byte) error {
-	if that.v != nil {
-		return json.Unmarshal(data, that.v)
-	}
-	var tmp interface{}
-	err := json.Unmarshal(data, &tmp)
-	if err != nil {
-		return err
-	}
-	that.Val(tmp)
-	return nil
-}
-
-//UnmarshalJSON Make JSONNode a Unmarshaler Interface compatible
-func (that *JSONNode) UnmarshalJSON(data []byte) error {
-	if len(data) == 0 {
-		return nil
-	}
-	if that.dontExpand && that.t == TypeUndefined {
-		return nil
-	}
-	if that.t == TypeValue {
-		return that.unmarshalValue(data)
-	}
-	if data[0] == '{' {
-		if that.t != TypeMap && that.t != TypeUndefined {
-			return ErrorTypeUnmarshaling
-		}
-		return that.unmarshalMap(data)
-	}
-	if data[0] == '[' {
-		if that.t != TypeArray && that.t != TypeUndefined {
-			return ErrorTypeUnmarshaling
-		}
-		return that.unmarshalArray(data)
-
-	}
-	if that.t == TypeUndefined {
-		return that.unmarshalValue(data)
-	}
-	return ErrorTypeUnmarshaling
-}
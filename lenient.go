@@ -0,0 +1,74 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//UnmarshalLenient set or not if Unmarshal coerce mismatching input into the values of that JSONNode and its children
+//
+//When a value set with Val expects:
+//
+//- a number: "42" or " 4.2e1 " are accepted
+//
+//- a bool: "true", "false", "1", "0", 1 and 0 are accepted
+//
+//- a string: numbers and booleans are accepted as their json text
+//
+//Anything else still fails like without UnmarshalLenient.
+//
+//recurse: if true, it will set all the children of that JSONNode with val
+func (that *JSONNode) UnmarshalLenient(val bool, recurse bool) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.lenient = val })
+	if recurse {
+		switch that.t {
+		case TypeMap:
+			for k := range that.m {
+				that.m[k].UnmarshalLenient(val, recurse)
+			}
+		case TypeArray:
+			for k := range that.a {
+				that.a[k].UnmarshalLenient(val, recurse)
+			}
+		}
+	}
+	return that
+}
+
+//coerce rewrite data so it can be unmarshaled in a value of type rt
+func coerce(data []byte, rt reflect.Type) ([]byte, bool) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	quoted := len(data) > 0 && data[0] == '"'
+	switch {
+	case isNumberKind(rt.Kind()) && quoted:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, false
+		}
+		s = strings.TrimSpace(s)
+		if _, err := strconv.ParseFloat(s, 64); err != nil || !json.Valid([]byte(s)) {
+			return nil, false
+		}
+		return []byte(s), true
+	case rt.Kind() == reflect.Bool:
+		s := string(data)
+		if quoted {
+			if err := json.Unmarshal(data, &s); err != nil {
+				return nil, false
+			}
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, false
+		}
+		return []byte(strconv.FormatBool(b)), true
+	case rt.Kind() == reflect.String && !quoted && len(data) > 0 && data[0] != '{' && data[0] != '[' && !isJSONNull(data):
+		s, err := json.Marshal(string(data))
+		return s, err == nil
+	}
+	return nil, false
+}
@@ -0,0 +1,141 @@
+package jsongo
+
+import (
+	"bytes"
+)
+
+//Conflict is a path changed differently in ours and theirs by Merge3
+//
+//Base, Ours and Theirs are nil when the path does not exist in the corresponding tree
+type Conflict struct {
+	Path   Path
+	Base   *JSONNode
+	Ours   *JSONNode
+	Theirs *JSONNode
+}
+
+//Merge3 merge the changes made from base in ours and in theirs and return the result in a new tree
+//
+//Maps are merged key by key and arrays index by index when they have the same length in the three trees.
+//A path changed on one side takes the new value, a path changed the same way on both sides too.
+//A path changed differently on both sides is a Conflict, the merged tree keeps ours for it.
+//
+//The error is not nil if a tree cannot be marshaled, to be compared
+func Merge3(base, ours, theirs *JSONNode) (*JSONNode, []Conflict, error) {
+	m := &merger{}
+	merged, err := m.merge(nil, base, ours, theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if merged == nil {
+		merged = &JSONNode{}
+	}
+	return merged, m.conflicts, nil
+}
+
+//MergePatch Return a new tree with patch applied to target as a JSON merge patch (RFC 7386)
+//
+//Maps are merged key by key, a null in patch removes the key and any other value replaces the one of target.
+//target can be nil, neither target nor patch are modified
+func MergePatch(target, patch *JSONNode) *JSONNode {
+	if patch.t != TypeMap {
+		return copyNode(patch)
+	}
+	merged := (&JSONNode{}).SetType(TypeMap)
+	if target != nil && target.t == TypeMap {
+		for key, child := range target.m {
+			merged.m[key] = copyNode(child)
+		}
+	}
+	for _, key := range sortedKeys(patch.m) {
+		child := patch.m[key]
+		if child.t == TypeNull {
+			delete(merged.m, key)
+			continue
+		}
+		merged.m[key] = MergePatch(merged.m[key], child)
+	}
+	return merged
+}
+
+//merger hold the state of a Merge3
+type merger struct {
+	conflicts []Conflict
+}
+
+//merge return the merged node at path, nil if it does not exist. base, ours and theirs can be nil
+func (m *merger) merge(path []pathElem, base, ours, theirs *JSONNode) (*JSONNode, error) {
+	if same, err := sameNode(ours, theirs); err != nil || same {
+		return copyNode(ours), err
+	}
+	if same, err := sameNode(base, ours); err != nil || same {
+		return copyNode(theirs), err
+	}
+	if same, err := sameNode(base, theirs); err != nil || same {
+		return copyNode(ours), err
+	}
+	switch {
+	case ours != nil && theirs != nil && ours.t == TypeMap && theirs.t == TypeMap:
+		var baseMap map[string]*JSONNode
+		if base != nil && base.t == TypeMap {
+			baseMap = base.m
+		}
+		merged := (&JSONNode{}).SetType(TypeMap)
+		keys := sortedKeys(ours.m)
+		for _, key := range sortedKeys(theirs.m) {
+			if _, ok := ours.m[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range keys {
+			child, err := m.merge(append(path, newKeyElem(key)), baseMap[key], ours.m[key], theirs.m[key])
+			if err != nil {
+				return nil, err
+			}
+			if child != nil {
+				merged.m[key] = child
+			}
+		}
+		return merged, nil
+	case base != nil && ours != nil && theirs != nil && base.t == TypeArray && ours.t == TypeArray && theirs.t == TypeArray &&
+		len(base.a) == len(ours.a) && len(base.a) == len(theirs.a):
+		merged := (&JSONNode{}).SetType(TypeArray)
+		for i := range base.a {
+			child, err := m.merge(append(path, pathElem{index: i, isIndex: true}), base.a[i], ours.a[i], theirs.a[i])
+			if err != nil {
+				return nil, err
+			}
+			if child == nil {
+				child = &JSONNode{}
+			}
+			merged.At(Append).Copy(child, false)
+		}
+		return merged, nil
+	}
+	m.conflicts = append(m.conflicts, Conflict{Path: Path{elems: append([]pathElem(nil), path...)}, Base: base, Ours: ours, Theirs: theirs})
+	return copyNode(ours), nil
+}
+
+//sameNode compare two JSONNodes by their canonical form, nil being a missing node
+func sameNode(a, b *JSONNode) (bool, error) {
+	if a == nil || b == nil {
+		return a == b, nil
+	}
+	ca, err := a.MarshalCanonical()
+	if err != nil {
+		return false, err
+	}
+	cb, err := b.MarshalCanonical()
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ca, cb), nil
+}
+
+//copyNode return a deep copy of node, nil if node is nil
+func copyNode(node *JSONNode) *JSONNode {
+	if node == nil {
+		return nil
+	}
+	return (&JSONNode{}).Copy(node, true)
+}
@@ -0,0 +1,88 @@
+package jsongo
+
+import (
+	"errors"
+	"strconv"
+)
+
+//ErrorNotArrayLike error if ToArray is called on a JSONNode which is not a TypeMap with the keys "0" to "n-1"
+var ErrorNotArrayLike = errors.New("jsongo: ToArray: JSONNode is not a TypeMap with the keys 0 to n-1")
+
+//NumericKeys set or not if At tolerate numeric keys on that JSONNode and its children
+//
+//With NumericKeys, At(0) on a TypeMap address the key "0", and At("0") on a TypeArray address the index 0,
+//so documents using objects with numeric keys in place of arrays can be walked like arrays. Use ToArray to convert them.
+//
+//recurse: if true, it will set all the children of that JSONNode with val
+func (that *JSONNode) NumericKeys(val bool, recurse bool) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.numeric = val })
+	if recurse {
+		switch that.t {
+		case TypeMap:
+			for k := range that.m {
+				that.m[k].NumericKeys(val, recurse)
+			}
+		case TypeArray:
+			for k := range that.a {
+				that.a[k].NumericKeys(val, recurse)
+			}
+		}
+	}
+	return that
+}
+
+//numericKey convert an At key to the type of that JSONNode if NumericKeys is set
+func (that *JSONNode) numericKey(key interface{}) interface{} {
+	if !that.opts().numeric {
+		return key
+	}
+	switch kk := key.(type) {
+	case int:
+		if that.t == TypeMap && kk >= 0 {
+			return strconv.Itoa(kk)
+		}
+	case string:
+		if that.t == TypeArray {
+			if i, ok := arrayIndex(kk); ok {
+				return i
+			}
+		}
+	}
+	return key
+}
+
+//arrayIndex return the index written in key, which must be in its canonical form ("1" and not "01" or "+1")
+func arrayIndex(key string) (int, bool) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || strconv.Itoa(i) != key {
+		return 0, false
+	}
+	return i, true
+}
+
+//ToArray turn that TypeMap JSONNode whose keys are "0" to "n-1" into a TypeArray, the child at "i" becoming the element i
+//
+//An empty TypeMap become an empty TypeArray. That JSONNode is left untouched if an error is returned,
+//ErrorFrozen if it is frozen
+func (that *JSONNode) ToArray() error {
+	if that.t != TypeMap {
+		return ErrorNotArrayLike
+	}
+	if that.frozen {
+		return ErrorFrozen
+	}
+	for key := range that.m {
+		if i, ok := arrayIndex(key); !ok || i >= len(that.m) {
+			return ErrorNotArrayLike
+		}
+	}
+	that.mutate()
+	defer that.logChange(that.snapshot())
+	a := make([]*JSONNode, len(that.m))
+	for i := range a {
+		a[i] = that.m[strconv.Itoa(i)]
+	}
+	that.t, that.m, that.a = TypeArray, nil, a
+	that.linkElems(0)
+	return nil
+}
@@ -0,0 +1,93 @@
+package jsongo
+
+import (
+	"slices"
+	"sort"
+)
+
+//keys return the keys of m in the order set by the MarshalOptions
+//
+//The keys are stored in e.keyBuf, call releaseKeys once done with them
+func (e *encodeState) keys(m map[string]*JSONNode) []string {
+	start := len(e.keyBuf)
+	for key := range m {
+		e.keyBuf = append(e.keyBuf, key)
+	}
+	keys := e.keyBuf[start:]
+	slices.Sort(keys)
+	if e.opts.KeyLess != nil {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return e.opts.KeyLess(keys[i], keys[j])
+		})
+	}
+	if len(e.opts.PinnedKeys) == 0 {
+		return keys
+	}
+	ordered := make([]string, 0, len(keys))
+	pinned := make(map[string]bool, len(e.opts.PinnedKeys))
+	for _, key := range e.opts.PinnedKeys {
+		if _, ok := m[key]; ok && !pinned[key] {
+			pinned[key] = true
+			ordered = append(ordered, key)
+		}
+	}
+	for _, key := range keys {
+		if !pinned[key] {
+			ordered = append(ordered, key)
+		}
+	}
+	return ordered
+}
+
+//releaseKeys free the keys stored by keys
+func (e *encodeState) releaseKeys(keys int) {
+	e.keyBuf = e.keyBuf[:len(e.keyBuf)-keys]
+}
+
+//NaturalLess compare a and b like humans do, the runs of digits being compared by their numeric value
+//
+//"item2" is then before "item10". Use it as MarshalOptions.KeyLess
+func NaturalLess(a, b string) bool {
+	for len(a) > 0 && len(b) > 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := digitRun(a), digitRun(b)
+			ta, tb := trimZeros(a[:na]), trimZeros(b[:nb])
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[na:], b[nb:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+//digitRun return the number of digits at the start of s
+func digitRun(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+func trimZeros(digits string) string {
+	for len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	return digits
+}
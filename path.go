@@ -0,0 +1,300 @@
+package jsongo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//ErrorPathSyntax error if a path string cannot be parsed
+var ErrorPathSyntax = errors.New("jsongo: invalid path syntax")
+
+//ErrorPathNotFound error if a path does not lead to an existing JSONNode
+var ErrorPathNotFound = errors.New("jsongo: path not found")
+
+//pathElem is one step of a path
+type pathElem struct {
+	key      string
+	index    int  //index in TypeArray, -1 if key is not a number
+	isIndex  bool //true if the step can only be an index ("[n]" syntax)
+	wildcard bool //true if the step match any child, only used by Query
+}
+
+//parsePath parse a path string like "a.b[0].c"
+//
+//keys are separated by '.', "[n]" is an index in a TypeArray ("[-1]" being Append) and a key made of digits can be used as an index too
+//
+//'\' escapes the next character so keys can contain '.', '[', '*' or '\'
+//
+//if wildcards is true, a "*" key or a "[*]" index match any child
+func parsePath(path string, wildcards bool) ([]pathElem, error) {
+	var elems []pathElem
+	if path == "" {
+		return elems, nil
+	}
+	var key strings.Builder
+	inKey := true
+	escaped := false
+	flushKey := func() {
+		if wildcards && !escaped && key.String() == "*" {
+			elems = append(elems, pathElem{index: -1, wildcard: true})
+		} else {
+			elems = append(elems, newKeyElem(key.String()))
+		}
+		key.Reset()
+		escaped = false
+	}
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\':
+			i++
+			if i == len(path) {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+			key.WriteByte(path[i])
+			inKey = true
+			escaped = true
+		case c == '.':
+			if inKey {
+				flushKey()
+			}
+			if i == len(path)-1 {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+			inKey = true
+		case c == '[':
+			if inKey && (key.Len() > 0 || i > 0) {
+				flushKey()
+			}
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+			if wildcards && path[i+1:i+end] == "*" {
+				elems = append(elems, pathElem{index: -1, isIndex: true, wildcard: true})
+			} else {
+				index, err := strconv.Atoi(path[i+1 : i+end])
+				if err != nil || (index < 0 && index != Append) {
+					return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+				}
+				elems = append(elems, pathElem{index: index, isIndex: true})
+			}
+			i += end
+			inKey = false
+			if i+1 < len(path) && path[i+1] != '.' && path[i+1] != '[' {
+				return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, path)
+			}
+		default:
+			key.WriteByte(c)
+			inKey = true
+		}
+	}
+	if inKey {
+		flushKey()
+	}
+	return elems, nil
+}
+
+func newKeyElem(key string) pathElem {
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || strconv.Itoa(index) != key {
+		index = -1
+	}
+	return pathElem{key: key, index: index}
+}
+
+//find return the JSONNode at the end of elems without building anything
+func (that *JSONNode) find(elems []pathElem) (*JSONNode, bool) {
+	cur := that
+	for _, elem := range elems {
+		switch {
+		case cur.t == TypeMap && !elem.isIndex:
+			next, ok := cur.m[elem.key]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case cur.t == TypeArray && elem.index >= 0 && elem.index < len(cur.a):
+			cur = cur.a[elem.index]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+//Lookup Return the JSONNode at path without building anything
+//
+//path looks like "a.b[0].c", see ParsePath
+func (that *JSONNode) Lookup(path string) (*JSONNode, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(that)
+}
+
+//Path is a compiled path that can be applied to many trees without parsing it again
+type Path struct {
+	elems []pathElem
+}
+
+//NewPath Return a Path made of keys, keys follow the same rules as At arguments
+func NewPath(keys ...interface{}) (Path, error) {
+	keys, err := normalizeKeys(keys)
+	if err != nil {
+		return Path{}, err
+	}
+	elems := make([]pathElem, len(keys))
+	for i, key := range keys {
+		switch kk := key.(type) {
+		case string:
+			elems[i] = newKeyElem(kk)
+		case int:
+			if kk < 0 && kk != Append {
+				return Path{}, ErrorArrayNegativeValue
+			}
+			elems[i] = pathElem{index: kk, isIndex: true}
+		}
+	}
+	return Path{elems: elems}, nil
+}
+
+//ParsePath Return the Path described by path, like "a.b[0].c"
+//
+//see parsePath for the syntax
+func ParsePath(path string) (Path, error) {
+	elems, err := parsePath(path, false)
+	if err != nil {
+		return Path{}, err
+	}
+	return Path{elems: elems}, nil
+}
+
+//MustParsePath is like ParsePath but panic if path cannot be parsed
+func MustParsePath(path string) Path {
+	p, err := ParsePath(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+//Len Return the number of steps in the Path
+func (p Path) Len() int {
+	return len(p.elems)
+}
+
+//Equal Return true if both Path lead to the same place
+func (p Path) Equal(other Path) bool {
+	if len(p.elems) != len(other.elems) {
+		return false
+	}
+	for i := range p.elems {
+		if p.elems[i] != other.elems[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//String Return the Path in the syntax understood by ParsePath
+func (p Path) String() string {
+	var b strings.Builder
+	for i, elem := range p.elems {
+		if elem.isIndex {
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(elem.index))
+			b.WriteByte(']')
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		for j := 0; j < len(elem.key); j++ {
+			switch elem.key[j] {
+			case '.', '[', '*', '\\':
+				b.WriteByte('\\')
+			}
+			b.WriteByte(elem.key[j])
+		}
+	}
+	return b.String()
+}
+
+//Get Return the JSONNode at the end of the Path without building anything
+func (p Path) Get(root *JSONNode) (*JSONNode, error) {
+	node, ok := root.find(p.elems)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrorPathNotFound, p.String())
+	}
+	return node, nil
+}
+
+//At Return the JSONNode at the end of the Path, building it like At would
+//
+//nothing is built if an error is returned
+func (p Path) At(root *JSONNode) (*JSONNode, error) {
+	if err := root.checkPath(p.elems); err != nil {
+		return nil, err
+	}
+	cur := root
+	for _, elem := range p.elems {
+		if elem.isIndex || (cur.t == TypeArray && elem.index >= 0) {
+			cur = cur.atArray(elem.index, nil)
+		} else {
+			cur = cur.atMap(elem.key, nil)
+		}
+	}
+	return cur, nil
+}
+
+//Set Build the Path in root like At would and set the value at the end of it
+//
+//The errors Val would panic with (ErrorFrozen, ErrorMultipleType, ErrorConstraint) are returned with the Path,
+//nothing is built or set then
+func (p Path) Set(root *JSONNode, val interface{}) error {
+	node, err := p.At(root)
+	if err != nil {
+		return err
+	}
+	if err := node.canVal(val); err != nil {
+		return fmt.Errorf("%w at %q", err, p.String())
+	}
+	node.Val(val)
+	return nil
+}
+
+//checkPath return the error that Path.At would encounter, without building anything
+func (that *JSONNode) checkPath(elems []pathElem) error {
+	cur := that
+	for _, elem := range elems {
+		if cur == nil {
+			return nil
+		}
+		if elem.isIndex || (cur.t == TypeArray && elem.index >= 0) {
+			if cur.t != TypeUndefined && cur.t != TypeArray {
+				return ErrorMultipleType
+			}
+			if cur.frozen && (cur.t == TypeUndefined || elem.index < 0 || elem.index >= len(cur.a)) {
+				return ErrorFrozen
+			}
+			if elem.index >= 0 && elem.index < len(cur.a) {
+				cur = cur.a[elem.index]
+			} else {
+				cur = nil
+			}
+			continue
+		}
+		if cur.t != TypeUndefined && cur.t != TypeMap {
+			return ErrorMultipleType
+		}
+		if _, ok := cur.m[elem.key]; !ok && cur.frozen {
+			return ErrorFrozen
+		}
+		cur = cur.m[elem.key]
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"io"
+)
+
+//pipeFlushSize is the size of the buffer of Pipe, it is written once it is reached
+const pipeFlushSize = 32 * 1024
+
+//Transform is a change applied by Pipe to the values whose path match a pattern, see RenameKey, DropPath and RewriteValue
+type Transform struct {
+	pattern string
+	rename  string
+	drop    bool
+	rewrite func(*JSONNode) error
+}
+
+//RenameKey Return a Transform renaming the keys of the values matching pattern to key
+func RenameKey(pattern, key string) Transform {
+	return Transform{pattern: pattern, rename: key}
+}
+
+//DropPath Return a Transform removing the values matching pattern, with their key or from their array
+func DropPath(pattern string) Transform {
+	return Transform{pattern: pattern, drop: true}
+}
+
+//RewriteValue Return a Transform calling fn on the values matching pattern, which are written as fn left them
+//
+//Each matching value is decoded in a new JSONNode, so only it is held in memory
+func RewriteValue(pattern string, fn func(*JSONNode) error) Transform {
+	return Transform{pattern: pattern, rewrite: fn}
+}
+
+//compiledTransform is a Transform with its pattern compiled
+type compiledTransform struct {
+	Transform
+	query *Query
+}
+
+//Pipe copy the json documents of r to w, applying transforms on the way
+//
+//Patterns use the CompileQuery syntax and are matched against the paths of the input, before any renaming:
+//"items[*].secret" match the secret key of every element of items, "[2]" the third element of the root array.
+//When several transforms match a value, a DropPath wins, the last RenameKey is used and the RewriteValue are applied in order.
+//
+//The documents are read token by token and written compact, separated by a newline, so they are never fully held in memory.
+//Numbers are written as they were read. Dropping the root of a document writes nothing for it
+func Pipe(r io.Reader, w io.Writer, transforms ...Transform) error {
+	p := &pipe{dec: json.NewDecoder(r), w: w, transforms: make([]compiledTransform, len(transforms))}
+	p.dec.UseNumber()
+	for i := range transforms {
+		q, err := CompileQuery(transforms[i].pattern)
+		if err != nil {
+			return err
+		}
+		p.transforms[i] = compiledTransform{Transform: transforms[i], query: q}
+	}
+	for p.dec.More() {
+		if _, drop := p.member(nil, ""); drop {
+			if err := p.dec.Decode(&skipValue{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.value(nil); err != nil {
+			return err
+		}
+		p.buf = append(p.buf, '\n')
+	}
+	if _, err := p.dec.Token(); err != io.EOF {
+		if err == nil {
+			err = ErrorTypeUnmarshaling
+		}
+		return err
+	}
+	return p.flush()
+}
+
+//pipe hold what is needed by Pipe
+type pipe struct {
+	dec        *json.Decoder
+	w          io.Writer
+	buf        []byte
+	transforms []compiledTransform
+}
+
+//member return the name to write for the value at path found with key, and if it is dropped
+func (p *pipe) member(path []pathElem, key string) (string, bool) {
+	for i := range p.transforms {
+		t := &p.transforms[i]
+		if matchElems(t.query.elems, path) != matchFull {
+			continue
+		}
+		if t.drop {
+			return "", true
+		}
+		if t.rename != "" {
+			key = t.rename
+		}
+	}
+	return key, false
+}
+
+//value copy the value at path, the decoder being right before it
+func (p *pipe) value(path []pathElem) error {
+	var node *JSONNode
+	for i := range p.transforms {
+		t := &p.transforms[i]
+		if t.rewrite == nil || matchElems(t.query.elems, path) != matchFull {
+			continue
+		}
+		if node == nil {
+			node = &JSONNode{}
+			if err := p.dec.Decode(node); err != nil {
+				return err
+			}
+		}
+		if err := t.rewrite(node); err != nil {
+			return err
+		}
+	}
+	if node != nil {
+		var err error
+		p.buf, err = node.AppendJSON(p.buf)
+		return err
+	}
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			err = p.object(path)
+		} else {
+			err = p.array(path)
+		}
+		if err != nil {
+			return err
+		}
+	case string:
+		p.buf = appendString(p.buf, tok)
+	case json.Number:
+		p.buf = append(p.buf, tok...)
+	case bool:
+		if tok {
+			p.buf = append(p.buf, "true"...)
+		} else {
+			p.buf = append(p.buf, "false"...)
+		}
+	case nil:
+		p.buf = append(p.buf, "null"...)
+	}
+	if len(p.buf) >= pipeFlushSize {
+		return p.flush()
+	}
+	return nil
+}
+
+//object copy the members of an object, its '{' being already read
+func (p *pipe) object(path []pathElem) error {
+	p.buf = append(p.buf, '{')
+	first := true
+	for p.dec.More() {
+		tok, err := p.dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		child := append(path, newKeyElem(key))
+		name, drop := p.member(child, key)
+		if drop {
+			if err := p.dec.Decode(&skipValue{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if !first {
+			p.buf = append(p.buf, ',')
+		}
+		first = false
+		p.buf = appendString(p.buf, name)
+		p.buf = append(p.buf, ':')
+		if err := p.value(child); err != nil {
+			return err
+		}
+	}
+	if _, err := p.dec.Token(); err != nil {
+		return err
+	}
+	p.buf = append(p.buf, '}')
+	return nil
+}
+
+//array copy the elements of an array, its '[' being already read
+func (p *pipe) array(path []pathElem) error {
+	p.buf = append(p.buf, '[')
+	first := true
+	for i := 0; p.dec.More(); i++ {
+		child := append(path, pathElem{index: i, isIndex: true})
+		if _, drop := p.member(child, ""); drop {
+			if err := p.dec.Decode(&skipValue{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if !first {
+			p.buf = append(p.buf, ',')
+		}
+		first = false
+		if err := p.value(child); err != nil {
+			return err
+		}
+	}
+	if _, err := p.dec.Token(); err != nil {
+		return err
+	}
+	p.buf = append(p.buf, ']')
+	return nil
+}
+
+func (p *pipe) flush() error {
+	_, err := p.w.Write(p.buf)
+	p.buf = p.buf[:0]
+	return err
+}
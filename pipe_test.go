@@ -0,0 +1,58 @@
+package jsongo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPipe(t *testing.T) {
+	double := func(node *JSONNode) error {
+		n, err := node.GetInt64()
+		if err != nil {
+			return err
+		}
+		node.Unset()
+		node.Val(n * 2)
+		return nil
+	}
+	tests := []struct {
+		name       string
+		in         string
+		transforms []Transform
+		want       string
+	}{
+		{"copy", `{"a": [1, 2.50, "x", true, null]}`, nil, `{"a":[1,2.50,"x",true,null]}` + "\n"},
+		{"rename in array", `{"items": [{"a": 1, "b": 2}, {"a": 3}]}`, []Transform{RenameKey("items[*].a", "x")},
+			`{"items":[{"x":1,"b":2},{"x":3}]}` + "\n"},
+		{"drop in array", `{"items": [{"a": 1, "b": 2}, {"b": 3}]}`, []Transform{DropPath("items[*].b")},
+			`{"items":[{"a":1},{}]}` + "\n"},
+		{"drop element", `[1, 2, 3]`, []Transform{DropPath("[1]")}, `[1,3]` + "\n"},
+		{"drop every element", `{"a": [1, 2], "b": 1}`, []Transform{DropPath("a[*]")}, `{"a":[],"b":1}` + "\n"},
+		{"drop wins", `{"a": 1, "b": 2}`, []Transform{RenameKey("a", "c"), DropPath("a")}, `{"b":2}` + "\n"},
+		{"rewrite renamed", `{"a": [1, 2]}`, []Transform{RenameKey("a", "b"), RewriteValue("a[*]", double)}, `{"b":[2,4]}` + "\n"},
+		{"documents", `{"a": 1} [2] 3`, []Transform{DropPath("a")}, "{}\n[2]\n3\n"},
+		{"drop root", `{"a": 1} [2]`, []Transform{DropPath("")}, ""},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := Pipe(strings.NewReader(test.in), &out, test.transforms...); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, out.String(), test.want)
+		}
+	}
+}
+
+func TestPipeErrors(t *testing.T) {
+	for _, in := range []string{`{"a": [1, 2`, `{"a": }`, `[1] ]`} {
+		if err := Pipe(strings.NewReader(in), &bytes.Buffer{}); err == nil {
+			t.Errorf("%s: no error", in)
+		}
+	}
+	if err := Pipe(strings.NewReader(`{}`), &bytes.Buffer{}, DropPath("a[")); err == nil {
+		t.Error("invalid pattern: no error")
+	}
+}
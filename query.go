@@ -0,0 +1,136 @@
+package jsongo
+
+import (
+	"sort"
+)
+
+//Query is a compiled path expression that can contain wildcards
+//
+//A Query is immutable, it can be compiled once and used by many goroutines at the same time
+type Query struct {
+	expr  string
+	elems []pathElem
+}
+
+//CompileQuery parse expr and return a Query
+//
+//expr use the ParsePath syntax where a "*" key match any key of a TypeMap or any index of a TypeArray
+//and a "[*]" index match any index of a TypeArray, like "items[*].id" or "metrics.*.latency"
+func CompileQuery(expr string) (*Query, error) {
+	elems, err := parsePath(expr, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{expr: expr, elems: elems}, nil
+}
+
+//MustCompileQuery is like CompileQuery but panic if expr cannot be parsed
+func MustCompileQuery(expr string) *Query {
+	q, err := CompileQuery(expr)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+//String Return the expression the Query was compiled from
+func (q *Query) String() string {
+	return q.expr
+}
+
+//Find Return every JSONNode matching the Query in root, nothing is built
+//
+//Map keys are visited in sorted order so the result is stable
+func (q *Query) Find(root *JSONNode) []*JSONNode {
+	var ret []*JSONNode
+	q.find(root, q.elems, &ret)
+	return ret
+}
+
+//First Return the first JSONNode Find would return
+func (q *Query) First(root *JSONNode) (*JSONNode, bool) {
+	found := q.Find(root)
+	if len(found) == 0 {
+		return nil, false
+	}
+	return found[0], true
+}
+
+func (q *Query) find(cur *JSONNode, elems []pathElem, ret *[]*JSONNode) {
+	if len(elems) == 0 {
+		*ret = append(*ret, cur)
+		return
+	}
+	elem := elems[0]
+	if !elem.wildcard {
+		if next, ok := cur.find(elems[:1]); ok {
+			q.find(next, elems[1:], ret)
+		}
+		return
+	}
+	switch cur.t {
+	case TypeMap:
+		if elem.isIndex {
+			return
+		}
+		for _, key := range sortedKeys(cur.m) {
+			q.find(cur.m[key], elems[1:], ret)
+		}
+	case TypeArray:
+		for i := range cur.a {
+			q.find(cur.a[i], elems[1:], ret)
+		}
+	}
+}
+
+//Match Return true if p is one of the paths the Query matches
+func (q *Query) Match(p Path) bool {
+	return matchElems(q.elems, p.elems) == matchFull
+}
+
+type matchResult int
+
+const (
+	matchNone   matchResult = iota
+	matchPrefix             //the path lead to matching paths, the query is longer
+	matchFull               //the path match the whole query
+	matchUnder              //the path is below a matching path
+)
+
+//matchElems compare a concrete path with query elements
+func matchElems(query, path []pathElem) matchResult {
+	for i := range query {
+		if i == len(path) {
+			return matchPrefix
+		}
+		if !matchElem(query[i], path[i]) {
+			return matchNone
+		}
+	}
+	if len(path) > len(query) {
+		return matchUnder
+	}
+	return matchFull
+}
+
+func matchElem(q, p pathElem) bool {
+	switch {
+	case q.wildcard:
+		return !q.isIndex || p.isIndex || p.index >= 0
+	case q.isIndex:
+		return q.index >= 0 && q.index == p.index
+	case p.isIndex:
+		return q.index >= 0 && q.index == p.index
+	}
+	return q.key == p.key
+}
+
+//sortedKeys return the keys of a TypeMap in sorted order
+func sortedKeys(m map[string]*JSONNode) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
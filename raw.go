@@ -0,0 +1,127 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+//ErrorRawType error if ToRawTree is called on a JSONNode which is not a TypeMap or ToRawArray on one which is not a TypeArray
+var ErrorRawType = errors.New("jsongo: raw conversion of a JSONNode of the wrong type")
+
+//ToRawTree Return the children of that TypeMap JSONNode encoded as json.RawMessage
+//
+//Children holding a json.RawMessage, like the ones built by FromRawTree, are returned as is without being encoded again
+func (that *JSONNode) ToRawTree() (map[string]json.RawMessage, error) {
+	if that.t != TypeMap {
+		return nil, ErrorRawType
+	}
+	ret := make(map[string]json.RawMessage, len(that.m))
+	for key, child := range that.m {
+		raw, err := child.toRaw()
+		if err != nil {
+			return nil, err
+		}
+		ret[key] = raw
+	}
+	return ret, nil
+}
+
+//ToRawArray Return the elements of that TypeArray JSONNode encoded as json.RawMessage, see ToRawTree
+func (that *JSONNode) ToRawArray() ([]json.RawMessage, error) {
+	if that.t != TypeArray {
+		return nil, ErrorRawType
+	}
+	ret := make([]json.RawMessage, len(that.a))
+	for i := range that.a {
+		raw, err := that.a[i].toRaw()
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = raw
+	}
+	return ret, nil
+}
+
+func (that *JSONNode) toRaw() (json.RawMessage, error) {
+	if raw, ok := that.rawValue(); ok {
+		return raw, nil
+	}
+	return that.MarshalJSON()
+}
+
+//FromRawTree Return a TypeMap JSONNode whose children hold the values of raw without decoding them
+//
+//The children are TypeValue holding a json.RawMessage, they are marshaled as they are.
+//Use ExpandRaw to turn them into JSONNode trees
+func FromRawTree(raw map[string]json.RawMessage) *JSONNode {
+	node := &JSONNode{}
+	node.SetType(TypeMap)
+	for key, val := range raw {
+		node.Map(key).Val(val)
+	}
+	return node
+}
+
+//FromRawArray Return a TypeArray JSONNode whose elements hold the values of raw without decoding them, see FromRawTree
+func FromRawArray(raw []json.RawMessage) *JSONNode {
+	node := &JSONNode{}
+	node.Resize(len(raw))
+	for i := range raw {
+		node.a[i].Val(raw[i])
+	}
+	return node
+}
+
+//ExpandRaw decode the json.RawMessage held by that JSONNode, or by its direct children, into JSONNode trees
+//
+//Values which are not json.RawMessage are left untouched. On error nothing is modified in the failing JSONNode,
+//ErrorFrozen is returned for a frozen JSONNode holding a json.RawMessage
+func (that *JSONNode) ExpandRaw() error {
+	switch that.t {
+	case TypeMap:
+		for _, key := range sortedKeys(that.m) {
+			if err := that.m[key].expandRaw(); err != nil {
+				return err
+			}
+		}
+	case TypeArray:
+		for i := range that.a {
+			if err := that.a[i].expandRaw(); err != nil {
+				return err
+			}
+		}
+	default:
+		return that.expandRaw()
+	}
+	return nil
+}
+
+func (that *JSONNode) expandRaw() error {
+	raw, ok := that.rawValue()
+	if !ok {
+		return nil
+	}
+	if that.frozen {
+		return ErrorFrozen
+	}
+	tmp := &JSONNode{}
+	if err := tmp.UnmarshalJSON(raw); err != nil {
+		return err
+	}
+	that.mutate()
+	defer that.logChange(that.snapshot())
+	that.t, that.m, that.a, that.v, that.vChanged = tmp.t, tmp.m, tmp.a, tmp.v, tmp.vChanged
+	if that.link != nil {
+		that.linkTo(that.link.tracker, that.link.path)
+	}
+	return nil
+}
+
+//rawValue return the json.RawMessage held by that JSONNode if any
+func (that *JSONNode) rawValue() (json.RawMessage, bool) {
+	if that.t != TypeValue || that.compute != nil {
+		return nil, false
+	}
+	raw, ok := that.Get().(json.RawMessage)
+	return raw, ok && raw != nil
+}
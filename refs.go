@@ -0,0 +1,165 @@
+package jsongo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//ErrorRefNotFound error if a $ref points to something that does not exist
+var ErrorRefNotFound = errors.New("jsongo: ResolveRefs: reference not found")
+
+//ErrorRefCycle error if a $ref ends up pointing to itself
+var ErrorRefCycle = errors.New("jsongo: ResolveRefs: reference cycle")
+
+//ErrorRefExternal error if a $ref points to another document and there is no RefOptions.Loader
+var ErrorRefExternal = errors.New("jsongo: ResolveRefs: external reference without loader")
+
+//RefMode decide how ResolveRefs replace a reference
+type RefMode int
+
+const (
+	//RefInline replace each reference by a deep copy of its target
+	RefInline RefMode = iota
+	//RefLink replace each reference by a shallow copy of its target, sharing the children
+	RefLink
+)
+
+//RefLoader load the document identified by uri, the part of a $ref before '#'
+type RefLoader func(uri string) (*JSONNode, error)
+
+//RefOptions are the options of ResolveRefs
+type RefOptions struct {
+	Mode   RefMode
+	Loader RefLoader //optional, needed for references to other documents
+}
+
+//refResolver hold the state of a ResolveRefs call
+type refResolver struct {
+	opts      RefOptions
+	docs      map[string]*JSONNode //documents already loaded
+	resolving map[*JSONNode]bool   //targets being resolved, to detect cycles
+	done      map[*JSONNode]bool   //nodes already resolved
+}
+
+//ResolveRefs replace every {"$ref": "..."} TypeMap in that JSONNode by the JSONNode it points to
+//
+//"#/definitions/x" is a JSON pointer in that JSONNode, "other.json#/x" needs a RefOptions.Loader.
+//
+//Other keys next to "$ref" are dropped
+func (that *JSONNode) ResolveRefs(opts RefOptions) error {
+	r := &refResolver{
+		opts:      opts,
+		docs:      map[string]*JSONNode{"": that},
+		resolving: make(map[*JSONNode]bool),
+		done:      make(map[*JSONNode]bool),
+	}
+	return r.resolve(that, that)
+}
+
+func (r *refResolver) resolve(node, doc *JSONNode) error {
+	if r.done[node] {
+		return nil
+	}
+	ref, isRef := refOf(node)
+	switch {
+	case isRef:
+		target, targetDoc, err := r.target(ref, doc)
+		if err != nil {
+			return err
+		}
+		if r.resolving[target] || target == node {
+			return fmt.Errorf("%w %q", ErrorRefCycle, ref)
+		}
+		r.resolving[target] = true
+		err = r.resolve(target, targetDoc)
+		delete(r.resolving, target)
+		if err != nil {
+			return err
+		}
+		if node.frozen {
+			return ErrorFrozen
+		}
+		node.Unset()
+		node.Copy(target, r.opts.Mode == RefInline)
+	case node.t == TypeMap:
+		r.resolving[node] = true
+		for _, key := range sortedKeys(node.m) {
+			if err := r.resolve(node.m[key], doc); err != nil {
+				return err
+			}
+		}
+		delete(r.resolving, node)
+	case node.t == TypeArray:
+		r.resolving[node] = true
+		for i := range node.a {
+			if err := r.resolve(node.a[i], doc); err != nil {
+				return err
+			}
+		}
+		delete(r.resolving, node)
+	}
+	r.done[node] = true
+	return nil
+}
+
+//target return the JSONNode a reference points to and the document it belongs to
+func (r *refResolver) target(ref string, doc *JSONNode) (*JSONNode, *JSONNode, error) {
+	uri, pointer := ref, ""
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		uri, pointer = ref[:i], ref[i+1:]
+	}
+	if uri != "" {
+		loaded, ok := r.docs[uri]
+		if !ok {
+			if r.opts.Loader == nil {
+				return nil, nil, fmt.Errorf("%w %q", ErrorRefExternal, ref)
+			}
+			var err error
+			if loaded, err = r.opts.Loader(uri); err != nil {
+				return nil, nil, err
+			}
+			r.docs[uri] = loaded
+		}
+		doc = loaded
+	}
+	elems, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, ok := doc.find(elems)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w %q", ErrorRefNotFound, ref)
+	}
+	return target, doc, nil
+}
+
+//refOf return the reference of a {"$ref": "..."} TypeMap
+func refOf(node *JSONNode) (string, bool) {
+	if node.t != TypeMap {
+		return "", false
+	}
+	child, ok := node.m["$ref"]
+	if !ok || child.t != TypeValue {
+		return "", false
+	}
+	ref, ok := child.Get().(string)
+	return ref, ok
+}
+
+//parsePointer parse a JSON pointer (RFC 6901) like "/definitions/a~1b"
+func parsePointer(pointer string) ([]pathElem, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %q", ErrorPathSyntax, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	elems := make([]pathElem, len(tokens))
+	for i, token := range tokens {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		elems[i] = newKeyElem(token)
+	}
+	return elems, nil
+}
@@ -0,0 +1,55 @@
+package jsongo
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+//ErrorRevisionMismatch error if CompareAndApply find a tree changed since the expected revision
+var ErrorRevisionMismatch = errors.New("jsongo: CompareAndApply: revision mismatch")
+
+//revisions is the last revision given to a change, shared by the trees so revisions never go back
+var revisions uint64
+
+//applyMu serialize the CompareAndApply calls
+var applyMu sync.Mutex
+
+//stamp give a new revision to the tree of that JSONNode, if Revision was called on it
+func (that *JSONNode) stamp() {
+	if that.link != nil && atomic.LoadUint64(&that.link.tracker.rev) != 0 {
+		atomic.StoreUint64(&that.link.tracker.rev, atomic.AddUint64(&revisions, 1))
+	}
+}
+
+//Revision Return the revision of the last change made to that JSONNode or one of its children
+//
+//Every change made through jsongo (Val, Map, At building a node, DelKey, Unmarshal...) gives a higher revision,
+//so two equal revisions mean nothing changed in between. Changes made directly to a value set with Val(&x) are not seen.
+//
+//The revisions are counted from the first call, which link the tree like AttachJournal does.
+//They are counted for the whole tree that JSONNode is tracked in: a change made to another part of it gives a new revision too
+func (that *JSONNode) Revision() uint64 {
+	t, _ := that.tracker()
+	atomic.CompareAndSwapUint64(&t.rev, 0, atomic.AddUint64(&revisions, 1))
+	return atomic.LoadUint64(&t.rev)
+}
+
+//CompareAndApply call patch on that JSONNode if its revision is still expectedRev and return the new revision
+//
+//ErrorRevisionMismatch is returned and patch is not called if the tree changed since expectedRev,
+//which makes ETag like conditional updates possible. The error of patch is returned as is,
+//the changes it made before failing are kept.
+//
+//CompareAndApply calls are serialized, other changes must not be made concurrently
+func (that *JSONNode) CompareAndApply(expectedRev uint64, patch func(node *JSONNode) error) (uint64, error) {
+	applyMu.Lock()
+	defer applyMu.Unlock()
+	if that.Revision() != expectedRev {
+		return 0, ErrorRevisionMismatch
+	}
+	if err := patch(that); err != nil {
+		return that.Revision(), err
+	}
+	return that.Revision(), nil
+}
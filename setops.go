@@ -0,0 +1,121 @@
+package jsongo
+
+import (
+	"errors"
+)
+
+//ErrorSetType error if a set operation is called on a JSONNode which is not a TypeArray
+var ErrorSetType = errors.New("jsongo: set operation on a JSONNode which is not a TypeArray")
+
+//KeyFunc return the key identifying an element in the set operations, elements with the same key are equal
+//
+//ok is false for an element without key, it is then equal to no other element.
+//A nil KeyFunc compares the elements structurally, with their Hash
+type KeyFunc func(elem *JSONNode) (key string, ok bool)
+
+//ByKey Return a KeyFunc comparing the elements by the JSONNode at path, like "id" or "user.email"
+//
+//Elements without a JSONNode at path have no key
+func ByKey(path string) KeyFunc {
+	elems := mustParseKey(path)
+	return func(elem *JSONNode) (string, bool) {
+		child, ok := elem.find(elems)
+		if !ok || child.t == TypeUndefined {
+			return "", false
+		}
+		data, err := child.MarshalCanonical()
+		if err != nil {
+			panic(err)
+		}
+		return string(data), true
+	}
+}
+
+//Unique Return a new TypeArray with deep copies of the elements of that TypeArray, without the duplicates
+//
+//The first element of each key is kept and the order is preserved.
+//Unique and the other set operations treat a TypeUndefined as an empty TypeArray and panic with ErrorSetType on anything else
+func (that *JSONNode) Unique(keyFn KeyFunc) *JSONNode {
+	return setOperation(keyFn, that).keep(that, nil)
+}
+
+//Union Return a new TypeArray with the unique elements of that TypeArray followed by the ones of other not in that TypeArray, see Unique
+func (that *JSONNode) Union(other *JSONNode, keyFn KeyFunc) *JSONNode {
+	s := setOperation(keyFn, that, other)
+	ret := s.keep(that, nil)
+	for i := range other.a {
+		key, ok := s.keyFn(other.a[i])
+		s.add(ret, other.a[i], key, ok)
+	}
+	return ret
+}
+
+//Intersect Return a new TypeArray with the unique elements of that TypeArray which are also in other, see Unique
+func (that *JSONNode) Intersect(other *JSONNode, keyFn KeyFunc) *JSONNode {
+	s := setOperation(keyFn, that, other)
+	in := s.keys(other)
+	return s.keep(that, func(key string, ok bool) bool { return ok && in[key] })
+}
+
+//Difference Return a new TypeArray with the unique elements of that TypeArray which are not in other, see Unique
+func (that *JSONNode) Difference(other *JSONNode, keyFn KeyFunc) *JSONNode {
+	s := setOperation(keyFn, that, other)
+	in := s.keys(other)
+	return s.keep(that, func(key string, ok bool) bool { return !ok || !in[key] })
+}
+
+//setOp hold the state of a set operation
+type setOp struct {
+	keyFn KeyFunc
+	seen  map[string]bool //keys already in the result
+}
+
+//setOperation check the operands and return a new setOp
+func setOperation(keyFn KeyFunc, operands ...*JSONNode) *setOp {
+	for _, operand := range operands {
+		if operand.t != TypeArray && operand.t != TypeUndefined {
+			panic(ErrorSetType)
+		}
+	}
+	if keyFn == nil {
+		keyFn = func(elem *JSONNode) (string, bool) {
+			hash := elem.Hash()
+			return string(hash[:]), true
+		}
+	}
+	return &setOp{keyFn: keyFn, seen: make(map[string]bool)}
+}
+
+//keys return the keys of the elements of node
+func (s *setOp) keys(node *JSONNode) map[string]bool {
+	keys := make(map[string]bool, len(node.a))
+	for i := range node.a {
+		if key, ok := s.keyFn(node.a[i]); ok {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+//keep return a new TypeArray with the elements of node whose key is accepted by filter, filter can be nil
+func (s *setOp) keep(node *JSONNode, filter func(key string, ok bool) bool) *JSONNode {
+	ret := (&JSONNode{}).SetType(TypeArray)
+	for i := range node.a {
+		key, ok := s.keyFn(node.a[i])
+		if filter == nil || filter(key, ok) {
+			s.add(ret, node.a[i], key, ok)
+		}
+	}
+	return ret
+}
+
+//add append a deep copy of elem, whose key is computed by the caller, to ret if its key is not already there
+func (s *setOp) add(ret *JSONNode, elem *JSONNode, key string, ok bool) {
+	if ok {
+		if s.seen[key] {
+			return
+		}
+		s.seen[key] = true
+	}
+	ret.At(Append).Copy(elem, true)
+}
@@ -0,0 +1,222 @@
+package jsongo
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+//EstimateJSONSize Return the number of bytes MarshalJSON would produce, without encoding anything
+//
+//The estimate is exact for trees made of strings, numbers, booleans and nil.
+//Other values (structs, Marshaler...) are encoded to be measured.
+//
+//return -1 if that JSONNode contains itself, MarshalJSON would fail with ErrorCycle
+func (that *JSONNode) EstimateJSONSize() int {
+	s := &sizer{root: that}
+	size := s.node(that)
+	if s.cycle {
+		return -1
+	}
+	return size
+}
+
+//sizer hold what is needed by EstimateJSONSize
+type sizer struct {
+	root  *JSONNode
+	stack []uintptr //identity of the containers being measured, to detect cycles
+	cycle bool      //a cycle was found, the size is meaningless
+}
+
+func (s *sizer) node(that *JSONNode) int {
+	if id := that.identity(); id != 0 {
+		if slices.Contains(s.stack, id) {
+			s.cycle = true
+			return 0
+		}
+		s.stack = append(s.stack, id)
+		defer func() { s.stack = s.stack[:len(s.stack)-1] }()
+	}
+	switch that.t {
+	case TypeMap:
+		size := 2
+		for key, child := range that.m {
+			size += estimateStringSize(key) + 1 + s.node(child)
+		}
+		if len(that.m) > 1 {
+			size += len(that.m) - 1
+		}
+		return size
+	case TypeArray:
+		size := 2
+		for i := range that.a {
+			size += s.node(that.a[i])
+		}
+		if len(that.a) > 1 {
+			size += len(that.a) - 1
+		}
+		return size
+	case TypeValue:
+		v := that.v
+		if that.compute != nil {
+			v = that.compute(s.root)
+		}
+		if f, bits, ok := floatValue(v); ok && that.opts().precision > 0 {
+			var buf [64]byte
+			return len(strconv.AppendFloat(buf[:0], f, 'f', that.opts().precision-1, bits))
+		}
+		return s.value(reflect.ValueOf(v))
+	}
+	return 4
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	nodeType          = reflect.TypeOf((*JSONNode)(nil))
+)
+
+func (s *sizer) value(rv reflect.Value) int {
+	if !rv.IsValid() {
+		return 4
+	}
+	if rv.Type() == nodeType && !rv.IsNil() {
+		return s.node(rv.Interface().(*JSONNode))
+	}
+	if rv.Kind() != reflect.Ptr && rv.Kind() != reflect.Interface && (rv.Type().Implements(marshalerType) || rv.Type().Implements(textMarshalerType)) {
+		return s.encoding(rv)
+	}
+	var buf [64]byte
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return 4
+		}
+		if rv.Type().Implements(marshalerType) || rv.Type().Implements(textMarshalerType) {
+			return s.encoding(rv)
+		}
+		return s.value(rv.Elem())
+	case reflect.Bool:
+		if rv.Bool() {
+			return 4
+		}
+		return 5
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return len(strconv.AppendInt(buf[:0], rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return len(strconv.AppendUint(buf[:0], rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return len(appendFloat(buf[:0], rv.Float(), rv.Type().Bits()))
+	case reflect.String:
+		if rv.Type() == reflect.TypeOf(json.Number("")) {
+			if rv.Len() == 0 {
+				return 1
+			}
+			return rv.Len()
+		}
+		return estimateStringSize(rv.String())
+	case reflect.Slice:
+		if rv.IsNil() {
+			return 4
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodedLen(rv.Len()) + 2
+		}
+		fallthrough
+	case reflect.Array:
+		size := 2
+		for i := 0; i < rv.Len(); i++ {
+			size += s.value(rv.Index(i))
+		}
+		if rv.Len() > 1 {
+			size += rv.Len() - 1
+		}
+		return size
+	case reflect.Map:
+		if rv.IsNil() {
+			return 4
+		}
+		if rv.Type().Key().Kind() != reflect.String {
+			return s.encoding(rv)
+		}
+		size := 2
+		iter := rv.MapRange()
+		for iter.Next() {
+			size += estimateStringSize(iter.Key().String()) + 1 + s.value(iter.Value())
+		}
+		if rv.Len() > 1 {
+			size += rv.Len() - 1
+		}
+		return size
+	}
+	return s.encoding(rv)
+}
+
+//encoding measure values we cant predict by encoding them
+func (s *sizer) encoding(rv reflect.Value) int {
+	if valueCycle(rv, s.stack, nil, map[uintptr]bool{}) != nil {
+		s.cycle = true
+		return 0
+	}
+	b, err := GetCodec().Marshal(rv.Interface())
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+//estimateStringSize return the size of s once quoted and escaped like encoding/json does
+func estimateStringSize(s string) int {
+	size := 2
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\' || c == '\n' || c == '\r' || c == '\t' || c == '\b' || c == '\f':
+				size += 2
+			case c < 0x20 || c == '<' || c == '>' || c == '&':
+				size += 6
+			default:
+				size++
+			}
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && n == 1 || r == '\u2028' || r == '\u2029' {
+			size += 6
+		} else {
+			size += n
+		}
+		i += n
+	}
+	return size
+}
+
+//appendFloat format f like encoding/json does
+func appendFloat(dst []byte, f float64, bits int) []byte {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+	if format == 'e' {
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
@@ -0,0 +1,210 @@
+package jsongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+//ErrorRequired error if Unmarshal or CheckRequired find a required JSONNode missing
+var ErrorRequired = errors.New("jsongo: required value is missing")
+
+//ErrorSpec error if BuildFromSpec is given an invalid spec
+var ErrorSpec = errors.New("jsongo: BuildFromSpec: invalid spec")
+
+//Required set or not if that JSONNode must be present in the input of Unmarshal
+//
+//Unmarshal return an error wrapping ErrorRequired if the TypeMap or TypeArray holding that JSONNode is in the input without it
+func (that *JSONNode) Required(val bool) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.required = val })
+	return that
+}
+
+//IsRequired Return true if Required was set on that JSONNode
+func (that *JSONNode) IsRequired() bool {
+	return that.opts().required
+}
+
+//CheckRequired Return an error wrapping ErrorRequired if a required JSONNode under that JSONNode is still TypeUndefined
+//
+//The typed values built by BuildFromSpec count as missing until they are given a value by Val, Unmarshal or Fill
+func (that *JSONNode) CheckRequired() error {
+	return that.checkRequired(nil)
+}
+
+func (that *JSONNode) checkRequired(path []pathElem) error {
+	if that.opts().required && (that.t == TypeUndefined || that.unfilled) {
+		return fmt.Errorf("%w at %q", ErrorRequired, Path{elems: path}.String())
+	}
+	switch that.t {
+	case TypeMap:
+		for _, key := range sortedKeys(that.m) {
+			if err := that.m[key].checkRequired(append(path, newKeyElem(key))); err != nil {
+				return err
+			}
+		}
+	case TypeArray:
+		for i := range that.a {
+			if err := that.a[i].checkRequired(append(path, pathElem{index: i, isIndex: true})); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//nodeSpec is a JSONNode described by BuildFromSpec
+type nodeSpec struct {
+	Type           string               `json:"type"`
+	Required       bool                 `json:"required"`
+	Default        json.RawMessage      `json:"default"`
+	DontExpand     bool                 `json:"dontExpand"`
+	GenerateOnly   []string             `json:"generateOnly"`
+	GenerateExcept []string             `json:"generateExcept"`
+	Enum           []interface{}        `json:"enum"`
+	MinLength      *int                 `json:"minLength"`
+	MaxLength      *int                 `json:"maxLength"`
+	Minimum        *float64             `json:"minimum"`
+	Maximum        *float64             `json:"maximum"`
+	Pattern        string               `json:"pattern"`
+	Tags           []string             `json:"tags"`
+	Properties     map[string]*nodeSpec `json:"properties"`
+	Items          []*nodeSpec          `json:"items"`
+}
+
+//BuildFromSpec build a tree ready to be Unmarshaled into from a json spec, like:
+//
+//	{
+//		"type": "object",
+//		"dontExpand": true,
+//		"properties": {
+//			"name": {"type": "string", "required": true, "minLength": 1},
+//			"level": {"type": "string", "default": "info", "enum": ["debug", "info", "warn"]},
+//			"port": {"type": "integer", "default": 8080, "minimum": 1, "maximum": 65535},
+//			"servers": {"type": "array", "items": [{"type": "object"}]}
+//		}
+//	}
+//
+//type is one of object (a TypeMap), array (a TypeArray), string, number, integer, boolean (a TypeValue
+//holding a string, a float64, an int64 or a bool), null or any (a TypeUndefined).
+//properties describe the children of an object and items the first elements of an array.
+//
+//The other keys call Required, Val with the default, UnmarshalDontExpand, GenerateOnly, GenerateExcept, Enum,
+//MinLen, MaxLen, Range, Matches and Tag. Unknown keys are an error.
+//
+//Other formats like YAML must be converted to json first
+func BuildFromSpec(spec []byte) (*JSONNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(spec))
+	dec.DisallowUnknownFields()
+	var root nodeSpec
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrorSpec, err)
+	}
+	node := &JSONNode{}
+	if err := root.build(node, nil); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+//build set node as described by s
+func (s *nodeSpec) build(node *JSONNode, path []pathElem) (err error) {
+	specError := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w at %q: %s", ErrorSpec, Path{elems: path}.String(), fmt.Sprintf(format, args...))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			recovered, ok := r.(error)
+			if !ok {
+				panic(r)
+			}
+			err = specError("%s", recovered.Error())
+		}
+	}()
+	switch s.Type {
+	case "object":
+		node.SetType(TypeMap)
+		for key, child := range s.Properties {
+			if child == nil {
+				return specError("property %q is null", key)
+			}
+			if err := child.build(node.Map(key), append(path, newKeyElem(key))); err != nil {
+				return err
+			}
+		}
+	case "array":
+		node.SetType(TypeArray)
+		for i, child := range s.Items {
+			if child == nil {
+				return specError("item %d is null", i)
+			}
+			if err := child.build(node.At(i), append(path, pathElem{index: i, isIndex: true})); err != nil {
+				return err
+			}
+		}
+	case "string":
+		node.Val(new(string))
+		node.unfilled = true
+	case "number":
+		node.Val(new(float64))
+		node.unfilled = true
+	case "integer":
+		node.Val(new(int64))
+		node.unfilled = true
+	case "boolean":
+		node.Val(new(bool))
+		node.unfilled = true
+	case "null":
+		node.SetNull()
+	case "any", "":
+	default:
+		return specError("unknown type %q", s.Type)
+	}
+	if (len(s.Properties) > 0 && s.Type != "object") || (len(s.Items) > 0 && s.Type != "array") {
+		return specError("properties and items need an object or an array type")
+	}
+	node.Required(s.Required)
+	node.UnmarshalDontExpand(s.DontExpand, false)
+	if len(s.GenerateOnly) > 0 {
+		node.GenerateOnly(s.GenerateOnly...)
+	}
+	if len(s.GenerateExcept) > 0 {
+		node.GenerateExcept(s.GenerateExcept...)
+	}
+	node.Tag(s.Tags...)
+	if len(s.Enum) > 0 {
+		node.Enum(s.Enum...)
+	}
+	if s.MinLength != nil {
+		node.Constrain(MinLen(*s.MinLength))
+	}
+	if s.MaxLength != nil {
+		node.Constrain(MaxLen(*s.MaxLength))
+	}
+	if s.Minimum != nil || s.Maximum != nil {
+		min, max := math.Inf(-1), math.Inf(1)
+		if s.Minimum != nil {
+			min = *s.Minimum
+		}
+		if s.Maximum != nil {
+			max = *s.Maximum
+		}
+		node.Constrain(Range(min, max))
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return specError("%s", err.Error())
+		}
+		node.Constrain(Matches(re))
+	}
+	if s.Default != nil {
+		if err := node.unmarshal(s.Default, &decodeState{path: path}); err != nil {
+			return specError("default: %s", err.Error())
+		}
+	}
+	return nil
+}
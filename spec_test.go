@@ -0,0 +1,49 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSpecRequiredTyped(t *testing.T) {
+	spec := []byte(`{"type": "object", "properties": {
+		"name": {"type": "string", "required": true},
+		"port": {"type": "integer", "required": true},
+		"debug": {"type": "boolean"}
+	}}`)
+	root, err := BuildFromSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := root.CheckRequired(); !errors.Is(err, ErrorRequired) {
+		t.Fatalf("got %v on the empty tree, want ErrorRequired", err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name": "a"}`), root); !errors.Is(err, ErrorRequired) {
+		t.Fatalf("got %v from Unmarshal, want ErrorRequired", err)
+	}
+	if err := root.CheckRequired(); !errors.Is(err, ErrorRequired) {
+		t.Fatalf("got %v with port missing, want ErrorRequired", err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name": "a", "port": 80}`), root); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.CheckRequired(); err != nil {
+		t.Fatalf("got %v once every required value is set", err)
+	}
+	if port, ok := root.At("port").Get().(*int64); !ok || *port != 80 {
+		t.Fatalf("got port %#v, want 80", root.At("port").Get())
+	}
+
+	root, err = BuildFromSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.At("name").Val("b")
+	root.At("port").Val(int64(1))
+	if err := root.CheckRequired(); err != nil {
+		t.Fatalf("got %v after Val", err)
+	}
+}
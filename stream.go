@@ -0,0 +1,284 @@
+package jsongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+//skipValue is decoded in place of the values we are not interested in, the decoder scans them without copying anything
+type skipValue struct{}
+
+func (*skipValue) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+//DecodePaths Unmarshal only the requested paths of data in a new JSONNode
+//
+//paths use the CompileQuery syntax. Every value whose path matches one of them is decoded with all its children,
+//everything else is skipped by the tokenizer without being built.
+//Array elements before a selected index are left as TypeUndefined.
+//A truncated data is an error, even when the requested paths were all found before its end
+func DecodePaths(data []byte, paths ...string) (*JSONNode, error) {
+	queries := make([]*Query, len(paths))
+	for i := range paths {
+		var err error
+		if queries[i], err = CompileQuery(paths[i]); err != nil {
+			return nil, err
+		}
+	}
+	s := &pathScanner{dec: json.NewDecoder(bytes.NewReader(data)), queries: queries, root: &JSONNode{}}
+	if err := s.value(nil); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return s.root, nil
+}
+
+//pathScanner walk the tokens of a decoder and decode the values matching its queries in root
+type pathScanner struct {
+	dec     *json.Decoder
+	queries []*Query
+	root    *JSONNode
+}
+
+//value handle the value at path, the decoder being right before it
+func (s *pathScanner) value(path []pathElem) error {
+	descend := false
+	for _, q := range s.queries {
+		switch matchElems(q.elems, path) {
+		case matchFull, matchUnder:
+			node, err := Path{elems: path}.At(s.root)
+			if err != nil {
+				return err
+			}
+			return s.dec.Decode(node)
+		case matchPrefix:
+			descend = true
+		}
+	}
+	if !descend {
+		return s.dec.Decode(&skipValue{})
+	}
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for s.dec.More() {
+			key, err := s.dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := s.value(append(path, newKeyElem(key.(string)))); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; s.dec.More(); i++ {
+			if err := s.value(append(path, pathElem{index: i, isIndex: true})); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	_, err = s.dec.Token()
+	return err
+}
+
+//EachElement decode one by one the elements of the arrays at path in r and call fn with each of them
+//
+//path use the CompileQuery syntax, "" being the root. Each element is decoded in a new JSONNode
+//which is not kept once fn returns, so arrays bigger than memory can be processed.
+//Values not on the way to path are skipped by the tokenizer.
+//EachElement stop and return the error of fn if it is not nil.
+//A truncated input is an error, returned after fn was called with the elements read before its end
+func EachElement(r io.Reader, path string, fn func(*JSONNode) error) error {
+	q, err := CompileQuery(path)
+	if err != nil {
+		return err
+	}
+	s := &elementScanner{dec: json.NewDecoder(r), query: q, fn: fn}
+	return unexpectedEOF(s.value(nil))
+}
+
+//unexpectedEOF turn the io.EOF of a decoder stopped in the middle of a document into io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+//elementScanner walk the tokens of a decoder and call fn on each element of the arrays matching query
+type elementScanner struct {
+	dec   *json.Decoder
+	query *Query
+	fn    func(*JSONNode) error
+}
+
+//value handle the value at path, the decoder being right before it
+func (s *elementScanner) value(path []pathElem) error {
+	match := matchElems(s.query.elems, path)
+	if match != matchFull && match != matchPrefix {
+		return s.dec.Decode(&skipValue{})
+	}
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		if match == matchFull {
+			return fmt.Errorf("%w: %s is not an array", ErrorTypeUnmarshaling, Path{elems: path})
+		}
+		for s.dec.More() {
+			key, err := s.dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := s.value(append(path, newKeyElem(key.(string)))); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; s.dec.More(); i++ {
+			if match == matchPrefix {
+				if err := s.value(append(path, pathElem{index: i, isIndex: true})); err != nil {
+					return err
+				}
+				continue
+			}
+			elem := &JSONNode{}
+			if err := s.dec.Decode(elem); err != nil {
+				return err
+			}
+			if err := s.fn(elem); err != nil {
+				return err
+			}
+		}
+	default:
+		if match == matchFull {
+			return fmt.Errorf("%w: %s is not an array", ErrorTypeUnmarshaling, Path{elems: path})
+		}
+		return nil
+	}
+	_, err = s.dec.Token()
+	return err
+}
+
+//DecodeAll Return an iterator over the json documents concatenated in r, each decoded in a new JSONNode
+//
+//Documents can be separated by any whitespace or nothing at all, and span several lines.
+//The iteration stops after the first error, which is yielded with a nil JSONNode
+func DecodeAll(r io.Reader) iter.Seq2[*JSONNode, error] {
+	return func(yield func(*JSONNode, error) bool) {
+		dec := json.NewDecoder(r)
+		for {
+			node := &JSONNode{}
+			err := dec.Decode(node)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(node, nil) {
+				return
+			}
+		}
+	}
+}
+
+//ErrorEncoderClosed error if you use an ArrayEncoder after calling Close
+var ErrorEncoderClosed = errors.New("jsongo: ArrayEncoder: already closed")
+
+//ArrayEncoder write a json array to an io.Writer one element at a time, see StreamTo
+type ArrayEncoder struct {
+	w      io.Writer
+	count  int   //number of elements written
+	err    error //first error encountered, returned by every following call
+	closed bool
+}
+
+//StreamTo write '[' and the elements of that JSONNode to w and return an ArrayEncoder to write the following elements
+//
+//that JSONNode must be a TypeArray or a TypeUndefined, it is not modified by the ArrayEncoder.
+//Call Close to write the closing ']'
+func (that *JSONNode) StreamTo(w io.Writer) (*ArrayEncoder, error) {
+	if that.t != TypeUndefined && that.t != TypeArray {
+		return nil, ErrorMultipleType
+	}
+	enc := &ArrayEncoder{w: w}
+	if _, err := w.Write([]byte{'['}); err != nil {
+		return nil, err
+	}
+	for i := range that.a {
+		if err := enc.Encode(that.a[i]); err != nil {
+			return nil, err
+		}
+	}
+	return enc, nil
+}
+
+//Encode write elem as the next element of the array and flush w if it can be flushed
+//
+//elem can be a *JSONNode or any value json.Marshal accepts
+func (enc *ArrayEncoder) Encode(elem interface{}) error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.closed {
+		return ErrorEncoderClosed
+	}
+	e := &encodeState{}
+	if enc.count > 0 {
+		e.buf = append(e.buf, ',')
+	}
+	if node, ok := elem.(*JSONNode); ok {
+		e.root = node
+	}
+	if enc.err = e.encodeValue(elem); enc.err != nil {
+		return enc.err
+	}
+	if _, enc.err = enc.w.Write(e.buf); enc.err != nil {
+		return enc.err
+	}
+	enc.count++
+	return enc.flush()
+}
+
+//Len Return the number of elements written so far
+func (enc *ArrayEncoder) Len() int {
+	return enc.count
+}
+
+//Close write the closing ']' and flush w if it can be flushed
+func (enc *ArrayEncoder) Close() error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.closed {
+		return ErrorEncoderClosed
+	}
+	enc.closed = true
+	if _, enc.err = enc.w.Write([]byte{']'}); enc.err != nil {
+		return enc.err
+	}
+	return enc.flush()
+}
+
+func (enc *ArrayEncoder) flush() error {
+	switch f := enc.w.(type) {
+	case interface{ Flush() error }:
+		enc.err = f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return enc.err
+}
@@ -0,0 +1,54 @@
+package jsongo
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const streamDoc = `{"a": {"b": [1, {"c": 2}], "d": "x"}, "e": [3, 4]}`
+
+func TestDecodePaths(t *testing.T) {
+	root, err := DecodePaths([]byte(streamDoc), "a.b[1].c", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustCanonical(t, root); string(got) != `{"a":{"b":[null,{"c":2}]},"e":[3,4]}` {
+		t.Fatalf("got %s", got)
+	}
+	if len(root.At("a").GetKeys()) != 1 {
+		t.Fatal("a.d was decoded")
+	}
+}
+
+func TestEachElement(t *testing.T) {
+	var got []string
+	err := EachElement(strings.NewReader(streamDoc), "e", func(elem *JSONNode) error {
+		got = append(got, string(mustCanonical(t, elem)))
+		return nil
+	})
+	if err != nil || strings.Join(got, ",") != "3,4" {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	stop := errors.New("stop")
+	if err := EachElement(strings.NewReader(streamDoc), "e", func(*JSONNode) error { return stop }); err != stop {
+		t.Fatalf("got %v, want the error of fn", err)
+	}
+	if err := EachElement(strings.NewReader(streamDoc), "a", func(*JSONNode) error { return nil }); !errors.Is(err, ErrorTypeUnmarshaling) {
+		t.Fatalf("got %v on an object, want ErrorTypeUnmarshaling", err)
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	for i := 0; i < len(streamDoc); i++ {
+		in := streamDoc[:i]
+		if _, err := DecodePaths([]byte(in), "a.b[1].c", "e"); err == nil || err == io.EOF {
+			t.Errorf("DecodePaths(%q): got %v", in, err)
+		}
+		err := EachElement(strings.NewReader(in), "e", func(*JSONNode) error { return nil })
+		if err == nil || err == io.EOF {
+			t.Errorf("EachElement(%q): got %v", in, err)
+		}
+	}
+}
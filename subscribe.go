@@ -0,0 +1,49 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+//Update is a change sent to the channel given to Subscribe
+type Update struct {
+	Path Path            //path of the JSONNode from the JSONNode Subscribe was called on
+	Old  json.RawMessage //json of the JSONNode before the change, nil if it was TypeUndefined
+	New  json.RawMessage //json of the JSONNode after the change, nil if it was removed or unset
+	Time time.Time
+}
+
+//subscription is a channel given to Subscribe and the pattern it listens to
+type subscription struct {
+	query  *Query
+	prefix []pathElem //path of the JSONNode Subscribe was called on
+	ch     chan<- Update
+}
+
+//Subscribe send to ch the changes made to the JSONNode under that JSONNode whose path match pattern
+//
+//pattern use the CompileQuery syntax, like "metrics.*.latency". Only the changes of the matching JSONNode themselves are sent,
+//not the ones of their parents or children. The changes are the ones recorded by AttachJournal,
+//the JSONNode not matching any subscription are not even encoded.
+//
+//Updates are sent without blocking: they are dropped when ch is full, give it a buffer big enough.
+//Call the returned function to stop the subscription, ch is not closed
+func (that *JSONNode) Subscribe(pattern string, ch chan<- Update) (func(), error) {
+	q, err := CompileQuery(pattern)
+	if err != nil {
+		return nil, err
+	}
+	t, prefix := that.tracker()
+	sub := &subscription{query: q, prefix: prefix, ch: ch}
+	t.mu.Lock()
+	t.subs = append(t.subs, sub)
+	t.mu.Unlock()
+	return func() {
+		t := t.current()
+		t.mu.Lock()
+		t.subs = slices.DeleteFunc(t.subs, func(s *subscription) bool { return s == sub })
+		t.mu.Unlock()
+		that.untrack(t)
+	}, nil
+}
@@ -0,0 +1,92 @@
+package jsongo
+
+import (
+	"testing"
+)
+
+func TestSubscribeSubtreeKeepsJournal(t *testing.T) {
+	var root JSONNode
+	root.At("metrics", "a", "latency").Val(1)
+	journal := NewJournal()
+	root.AttachJournal(journal)
+
+	updates := make(chan Update, 10)
+	stop, err := root.At("metrics").Subscribe("*.latency", updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.At("metrics", "a", "latency").Val(2)
+	root.At("other").Val(3)
+	if journal.Len() != 2 {
+		t.Fatalf("journal has %d entries, want 2", journal.Len())
+	}
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(updates))
+	}
+	if u := <-updates; u.Path.String() != "a.latency" || string(u.New) != "2" {
+		t.Fatalf("got update %s %s", u.Path, u.New)
+	}
+
+	stop()
+	root.At("metrics", "a", "latency").Val(4)
+	if journal.Len() != 3 || len(updates) != 0 {
+		t.Fatalf("journal has %d entries and %d updates after stop", journal.Len(), len(updates))
+	}
+	if entries := journal.Entries(); entries[2].Path.String() != "metrics.a.latency" {
+		t.Fatalf("got path %s", entries[2].Path)
+	}
+}
+
+func TestSubscribeSubtreeFirst(t *testing.T) {
+	var root JSONNode
+	root.At("metrics", "a", "latency").Val(1)
+	updates := make(chan Update, 10)
+	stop, err := root.At("metrics").Subscribe("*.latency", updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal := NewJournal()
+	root.AttachJournal(journal)
+	rev := root.Revision()
+
+	root.At("metrics", "a", "latency").Val(2)
+	if journal.Len() != 1 {
+		t.Fatalf("journal has %d entries, want 1", journal.Len())
+	}
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(updates))
+	}
+	if u := <-updates; u.Path.String() != "a.latency" {
+		t.Fatalf("got update %s", u.Path)
+	}
+	if root.Revision() <= rev {
+		t.Fatal("the revision did not change")
+	}
+
+	stop()
+	root.At("metrics", "a", "latency").Val(3)
+	if journal.Len() != 2 || len(updates) != 0 {
+		t.Fatalf("journal has %d entries and %d updates after stop", journal.Len(), len(updates))
+	}
+}
+
+func TestJournalSubtreeFirst(t *testing.T) {
+	var root JSONNode
+	root.At("a", "b").Val(1)
+	sub := NewJournal()
+	root.At("a").AttachJournal(sub)
+	rev := root.At("a").Revision()
+	all := NewJournal()
+	root.AttachJournal(all)
+
+	root.At("a", "b").Val(2)
+	if sub.Len() != 1 || all.Len() != 1 {
+		t.Fatalf("journals have %d and %d entries, want 1 and 1", sub.Len(), all.Len())
+	}
+	if p := sub.Entries()[0].Path.String(); p != "b" {
+		t.Fatalf("got path %s", p)
+	}
+	if root.At("a").Revision() <= rev {
+		t.Fatal("the revision went back")
+	}
+}
@@ -0,0 +1,64 @@
+package jsongo
+
+import (
+	"slices"
+)
+
+//Tag add tags to that JSONNode, like "pii" or "internal"
+//
+//Tags are never marshaled, they classify nodes for the code walking the tree (see SelectTagged).
+//Copy, Detach and Unmarshal keep them
+func (that *JSONNode) Tag(tags ...string) *JSONNode {
+	that.setOptions(func(o *nodeOptions) {
+		o.tags = slices.Clip(o.tags)
+		for _, tag := range tags {
+			if !slices.Contains(o.tags, tag) {
+				o.tags = append(o.tags, tag)
+			}
+		}
+	})
+	return that
+}
+
+//Untag remove tags from that JSONNode
+func (that *JSONNode) Untag(tags ...string) *JSONNode {
+	that.setOptions(func(o *nodeOptions) {
+		o.tags = slices.DeleteFunc(slices.Clone(o.tags), func(tag string) bool {
+			return slices.Contains(tags, tag)
+		})
+	})
+	return that
+}
+
+//HasTag Return true if that JSONNode has tag
+func (that *JSONNode) HasTag(tag string) bool {
+	return slices.Contains(that.opts().tags, tag)
+}
+
+//Tags Return the tags of that JSONNode
+func (that *JSONNode) Tags() []string {
+	return slices.Clone(that.opts().tags)
+}
+
+//SelectTagged Return that JSONNode and its children having tag, map keys being visited in sorted order
+func (that *JSONNode) SelectTagged(tag string) []*JSONNode {
+	var found []*JSONNode
+	that.selectTagged(tag, &found)
+	return found
+}
+
+func (that *JSONNode) selectTagged(tag string, found *[]*JSONNode) {
+	if that.HasTag(tag) {
+		*found = append(*found, that)
+	}
+	switch that.t {
+	case TypeMap:
+		for _, key := range sortedKeys(that.m) {
+			that.m[key].selectTagged(tag, found)
+		}
+	case TypeArray:
+		for i := range that.a {
+			that.a[i].selectTagged(tag, found)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package jsongo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTagsDeepCopy(t *testing.T) {
+	var src JSONNode
+	src.Tag("root")
+	src.At("user", "email").Tag("pii").Val("a@b.c")
+	src.At("list").Tag("list")
+	src.At("list", 0).Tag("elem").Val(1)
+
+	var dst JSONNode
+	dst.Copy(&src, true)
+	for _, want := range []struct {
+		keys []interface{}
+		tag  string
+	}{{nil, "root"}, {[]interface{}{"user", "email"}, "pii"}, {[]interface{}{"list"}, "list"}, {[]interface{}{"list", 0}, "elem"}} {
+		if !dst.At(want.keys...).HasTag(want.tag) {
+			t.Fatalf("%v lost tag %q", want.keys, want.tag)
+		}
+	}
+	dst.At("user", "email").Tag("copy")
+	if src.At("user", "email").HasTag("copy") {
+		t.Fatal("tagging the copy tagged the original")
+	}
+}
+
+func TestTagsDetach(t *testing.T) {
+	var src JSONNode
+	src.At("user", "email").Tag("pii").Val("a@b.c")
+	var dst JSONNode
+	dst.Copy(&src, false)
+	dst.Detach()
+	dst.At("user", "email").Untag("pii")
+	if !src.At("user", "email").HasTag("pii") {
+		t.Fatal("untagging the detached tree untagged the original")
+	}
+}
+
+func TestTagsUnmarshal(t *testing.T) {
+	var node JSONNode
+	node.At("email").Tag("pii")
+	node.At("list", 0).Tag("elem")
+	if err := node.UnmarshalJSON([]byte(`{"email":"a@b.c","list":[1,2]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := node.SelectTagged("pii"); len(got) != 1 || got[0].Get() != "a@b.c" {
+		t.Fatalf("got %v", got)
+	}
+	if tags := node.At("list", 0).Tags(); !slices.Equal(tags, []string{"elem"}) {
+		t.Fatalf("got %v", tags)
+	}
+}
+
+func TestCopyOptions(t *testing.T) {
+	var src JSONNode
+	src.Constrain(MinLen(1)).SetType(TypeMap)
+	var dst JSONNode
+	dst.Copy(&src, true)
+	if flags := dst.flags(); !slices.Contains(flags, "constrained") {
+		t.Fatalf("got flags %v", flags)
+	}
+}
@@ -0,0 +1,70 @@
+package jsongo
+
+//Field Return the child at key of that TypeMap, or nil if there is none
+//
+//Field, Index, Entries, Items and TemplateValue never build anything and accept a nil JSONNode,
+//so missing data renders as empty in text/template and html/template:
+//
+//	{{(.Field "user").Field "name"}}  {{range .Items}}{{.TemplateValue}}{{end}}  {{len .Entries}}  {{index .Entries "id"}}
+func (that *JSONNode) Field(key string) *JSONNode {
+	if that == nil || that.t != TypeMap {
+		return nil
+	}
+	return that.m[key]
+}
+
+//Index Return the element i of that TypeArray, or nil if there is none
+func (that *JSONNode) Index(i int) *JSONNode {
+	if that == nil || that.t != TypeArray || i < 0 || i >= len(that.a) {
+		return nil
+	}
+	return that.a[i]
+}
+
+//Entries Return the children of that TypeMap by key, nil for other types
+//
+//range over it in a template visits the keys in sorted order
+func (that *JSONNode) Entries() map[string]*JSONNode {
+	if that == nil || that.t != TypeMap {
+		return nil
+	}
+	entries := make(map[string]*JSONNode, len(that.m))
+	for key, child := range that.m {
+		entries[key] = child
+	}
+	return entries
+}
+
+//Items Return the elements of that TypeArray, nil for other types
+func (that *JSONNode) Items() []*JSONNode {
+	if that == nil || that.t != TypeArray {
+		return nil
+	}
+	items := make([]*JSONNode, len(that.a))
+	for i := range that.a {
+		items[i] = that.a[i]
+	}
+	return items
+}
+
+//TemplateValue Return what a template should print for that JSONNode
+//
+//the value of a TypeValue (computed values are evaluated with that JSONNode as root), Entries for a TypeMap,
+//Items for a TypeArray and nil for anything else
+func (that *JSONNode) TemplateValue() interface{} {
+	if that == nil {
+		return nil
+	}
+	switch that.t {
+	case TypeValue:
+		if that.compute != nil {
+			return that.compute(that)
+		}
+		return indirect(that.Get())
+	case TypeMap:
+		return that.Entries()
+	case TypeArray:
+		return that.Items()
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package jsongo
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+//LayoutUnix can be used as a layout with SetTime and GetTime to store time as epoch seconds
+const LayoutUnix = "unix"
+
+//LayoutUnixMilli can be used as a layout with SetTime and GetTime to store time as epoch milliseconds
+const LayoutUnixMilli = "unixmilli"
+
+//ErrorTimeType error if you try to GetTime on a value that is neither a string nor a number
+var ErrorTimeType = errors.New("jsongo: GetTime: value is neither a string nor a number")
+
+//SetTime Turn this JSONNode to Value type and set it to t formatted with layout
+//
+//layout defaults to time.RFC3339 when empty, LayoutUnix and LayoutUnixMilli store a number
+func (that *JSONNode) SetTime(t time.Time, layout string) {
+	switch layout {
+	case "":
+		that.Val(t.Format(time.RFC3339))
+	case LayoutUnix:
+		that.Val(t.Unix())
+	case LayoutUnixMilli:
+		that.Val(t.UnixMilli())
+	default:
+		that.Val(t.Format(layout))
+	}
+}
+
+//GetTime Return the value of a TypeValue as a time.Time
+//
+//strings are parsed with each layout in order (time.RFC3339 if none are given)
+//
+//numbers are epoch seconds, or epoch milliseconds if LayoutUnixMilli is part of the layouts.
+//ErrorIntOverflow is returned for numbers out of the int64 range
+func (that *JSONNode) GetTime(layouts ...string) (time.Time, error) {
+	if that.t != TypeValue {
+		return time.Time{}, ErrorRetrieveUserValue
+	}
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	millis := false
+	for _, layout := range layouts {
+		if layout == LayoutUnixMilli {
+			millis = true
+		}
+	}
+	switch v := that.Get().(type) {
+	case string:
+		var err error
+		for _, layout := range layouts {
+			if layout == LayoutUnix || layout == LayoutUnixMilli {
+				var n int64
+				if n, err = strconv.ParseInt(v, 10, 64); err == nil {
+					return epochTime(n, layout == LayoutUnixMilli), nil
+				}
+				continue
+			}
+			var t time.Time
+			if t, err = time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, err
+	case time.Time:
+		return v, nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return epochTime(n, millis), nil
+	case float64:
+		return floatEpochTime(v, millis)
+	case float32:
+		return floatEpochTime(float64(v), millis)
+	case int:
+		return epochTime(int64(v), millis), nil
+	case int32:
+		return epochTime(int64(v), millis), nil
+	case int64:
+		return epochTime(v, millis), nil
+	case uint:
+		return uintEpochTime(uint64(v), millis)
+	case uint32:
+		return epochTime(int64(v), millis), nil
+	case uint64:
+		return uintEpochTime(v, millis)
+	}
+	return time.Time{}, ErrorTimeType
+}
+
+func epochTime(n int64, millis bool) time.Time {
+	if millis {
+		return time.UnixMilli(n)
+	}
+	return time.Unix(n, 0)
+}
+
+//floatEpochTime return epochTime of f, truncated, or ErrorIntOverflow if it does not fit in an int64
+func floatEpochTime(f float64, millis bool) (time.Time, error) {
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return time.Time{}, ErrorIntOverflow
+	}
+	return epochTime(int64(f), millis), nil
+}
+
+//uintEpochTime return epochTime of n, or ErrorIntOverflow if it does not fit in an int64
+func uintEpochTime(n uint64, millis bool) (time.Time, error) {
+	if n > math.MaxInt64 {
+		return time.Time{}, ErrorIntOverflow
+	}
+	return epochTime(int64(n), millis), nil
+}
@@ -0,0 +1,48 @@
+package jsongo
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGetTimeRange(t *testing.T) {
+	tests := []struct {
+		name string
+		val  interface{}
+		want int64 //epoch seconds, ignored when err is set
+		err  error
+	}{
+		{"int64", int64(1700000000), 1700000000, nil},
+		{"uint64", uint64(1700000000), 1700000000, nil},
+		{"uint MaxInt64", uint(math.MaxInt64), math.MaxInt64, nil},
+		{"float64", 1700000000.5, 1700000000, nil},
+		{"uint64 above MaxInt64", uint64(1) << 63, 0, ErrorIntOverflow},
+		{"uint above MaxInt64", uint(math.MaxUint64), 0, ErrorIntOverflow},
+		{"float64 above MaxInt64", 1e19, 0, ErrorIntOverflow},
+		{"float64 below MinInt64", -1e19, 0, ErrorIntOverflow},
+		{"float32 above MaxInt64", float32(1e19), 0, ErrorIntOverflow},
+		{"NaN", math.NaN(), 0, ErrorIntOverflow},
+		{"bool", true, 0, ErrorTimeType},
+	}
+	for _, test := range tests {
+		var node JSONNode
+		node.Val(test.val)
+		got, err := node.GetTime(LayoutUnix)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("%s: got %v, %v, want %v", test.name, got, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got.Unix() != test.want {
+			t.Errorf("%s: got %v, %v, want %d", test.name, got.Unix(), err, test.want)
+		}
+	}
+	var node JSONNode
+	node.Val(int64(1700000000123))
+	if got, err := node.GetTime(LayoutUnixMilli); err != nil || !got.Equal(time.UnixMilli(1700000000123)) {
+		t.Errorf("millis: got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,63 @@
+package jsongo
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+//TruncateOptions are the limits of MarshalTruncated, a limit <= 0 is no limit
+type TruncateOptions struct {
+	MaxDepth  int //maps and arrays nested deeper are replaced by a string like "…(object of 12 keys)", that JSONNode is at depth 1
+	MaxItems  int //elements of arrays after MaxItems are replaced by a string like "…(+4120 items)", and keys of maps by a "…" key
+	MaxString int //strings longer than MaxString runes are cut and end with "…(+120 chars)"
+}
+
+//MarshalTruncated Return the json encoding of that JSONNode within the limits of opts, to log big trees safely
+//
+//The result is valid json but not the same document, it is not meant to be Unmarshaled back
+func (that *JSONNode) MarshalTruncated(opts TruncateOptions) ([]byte, error) {
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = -1
+	}
+	e := &encodeState{root: that, limits: &opts}
+	if err := e.encode(that); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+//tooDeep return true if node is a map or an array beyond MaxDepth
+func (e *encodeState) tooDeep(node *JSONNode) bool {
+	return e.limits.MaxDepth > 0 && (node.t == TypeMap || node.t == TypeArray) && len(e.path) >= e.limits.MaxDepth
+}
+
+//containerMarker return the string replacing a map or an array beyond MaxDepth
+func containerMarker(node *JSONNode) string {
+	if node.t == TypeMap {
+		return fmt.Sprintf("…(object of %d keys)", len(node.m))
+	}
+	return fmt.Sprintf("…(array of %d items)", len(node.a))
+}
+
+//encodeMarkerKey write the key replacing the last n keys of a map
+func (e *encodeState) encodeMarkerKey(n int) error {
+	if err := e.encodeString("…"); err != nil {
+		return err
+	}
+	e.buf = append(e.buf, ':')
+	return e.encodeString(fmt.Sprintf("(+%d keys)", n))
+}
+
+//truncateString cut s after max runes
+func truncateString(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	i, n := 0, 0
+	for n < max {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return fmt.Sprintf("%s…(+%d chars)", s[:i], utf8.RuneCountInString(s[i:]))
+}
@@ -0,0 +1,120 @@
+package jsongo
+
+import (
+	"reflect"
+)
+
+//CopyValues set or not if Val deep copy the values given to that JSONNode and its children
+//
+//With CopyValues, the maps, slices, arrays, pointers and structs given to Val are copied so the tree owns them:
+//modifying them afterward does not change the tree, and Unmarshal does not write in them anymore.
+//Unexported struct fields are copied shallowly. Use ValNoCopy to skip the copy of a value.
+//
+//recurse: if true, it will set all the children of that JSONNode with val
+func (that *JSONNode) CopyValues(val bool, recurse bool) *JSONNode {
+	that.setOptions(func(o *nodeOptions) { o.copyValues = val })
+	if recurse {
+		switch that.t {
+		case TypeMap:
+			for k := range that.m {
+				that.m[k].CopyValues(val, recurse)
+			}
+		case TypeArray:
+			for k := range that.a {
+				that.a[k].CopyValues(val, recurse)
+			}
+		}
+	}
+	return that
+}
+
+//ValNoCopy is Val without the copy of CopyValues, val is shared with the caller
+func (that *JSONNode) ValNoCopy(val interface{}) {
+	that.mutate()
+	if that.t != TypeUndefined && that.t != TypeValue {
+		panic(ErrorMultipleType)
+	}
+	if err := that.check(val); err != nil {
+		panic(err)
+	}
+	defer that.logChange(that.snapshot())
+	that.setVal(val)
+}
+
+//canVal return the error Val would panic with for val
+func (that *JSONNode) canVal(val interface{}) error {
+	if that.frozen {
+		return ErrorFrozen
+	}
+	if that.t != TypeUndefined && that.t != TypeValue {
+		return ErrorMultipleType
+	}
+	return that.check(val)
+}
+
+//deepCopy return a copy of v sharing no memory with it
+func deepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(v), map[uintptr]reflect.Value{}).Interface()
+}
+
+//deepCopyValue copy v, seen hold the copies of the pointers already copied to keep the cycles and the sharing
+func deepCopyValue(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if cp, ok := seen[v.Pointer()]; ok {
+			return cp
+		}
+		cp := reflect.New(v.Type().Elem())
+		seen[v.Pointer()] = cp
+		cp.Elem().Set(deepCopyValue(v.Elem(), seen))
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(deepCopyValue(v.Elem(), seen))
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopyValue(v.Index(i), seen))
+		}
+		return cp
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopyValue(v.Index(i), seen))
+		}
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(deepCopyValue(iter.Key(), seen), deepCopyValue(iter.Value(), seen))
+		}
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if cp.Field(i).CanSet() {
+				cp.Field(i).Set(deepCopyValue(v.Field(i), seen))
+			}
+		}
+		return cp
+	}
+	return v
+}